@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"fmt"
+	"sync"
+
+	"visionflow/database"
+	aiservice "visionflow/service/ai"
+)
+
+// BudgetWarning is emitted on "ai:budget:warning" when a call goes past a warn-only budget,
+// or runs unchecked against a blocking budget because its model has no known price
+type BudgetWarning struct {
+	JobID    string                  `json:"jobId"`
+	Budget   database.SpendingBudget `json:"budget"`
+	Spent    float64                 `json:"spent"`
+	Reserved float64                 `json:"reserved"` // Estimated cost of calls still running
+	Estimate float64                 `json:"estimate"`
+	Unpriced bool                    `json:"unpriced,omitempty"`
+}
+
+// BudgetExceededError is returned when a call would push spending past a blocking budget
+type BudgetExceededError struct {
+	Budget   database.SpendingBudget
+	Spent    float64
+	Reserved float64
+	Estimate float64
+}
+
+func (e *BudgetExceededError) Error() string {
+	scope := string(e.Budget.Scope)
+	if e.Budget.Scope != database.BudgetScopeGlobal {
+		scope = fmt.Sprintf("%s %d", e.Budget.Scope, e.Budget.ScopeID)
+	}
+	message := fmt.Sprintf("%s %s budget of $%.2f would be exceeded: $%.2f already spent", e.Budget.Period, scope, e.Budget.Limit, e.Spent)
+	if e.Reserved > 0 {
+		message += fmt.Sprintf(", $%.2f reserved by running calls", e.Reserved)
+	}
+	return message + fmt.Sprintf(", this call is estimated at $%.2f", e.Estimate)
+}
+
+// budgetReservations holds the estimated cost of calls that passed checkBudgets but are not in the usage ledger yet,
+// so calls started together cannot all slip under the same limit
+var budgetReservations = struct {
+	sync.Mutex
+	pending map[int]float64 // Reserved USD per budget ID
+}{pending: map[int]float64{}}
+
+// checkBudgets estimates the cost of a call and compares it with every budget covering the project and provider,
+// counting the estimates of calls still running. Blocking budgets refuse the call, warn-only budgets emit a BudgetWarning
+// and let it run. The estimate stays reserved until the returned release is called, once the call's usage has been
+// recorded or it failed.
+func checkBudgets(jobID string, projectID, providerID int, model string, amount aiservice.UsageAmount) (func(), error) {
+	budgets, err := database.ListApplicableBudgets(projectID, providerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load spending budgets: %w", err)
+	}
+	if len(budgets) == 0 {
+		return func() {}, nil
+	}
+
+	estimate, priced := aiservice.EstimateCost(model, amount)
+	if !priced {
+		// Refusing would lock out free local models, so blocking budgets only report that they were not checked
+		for _, budget := range budgets {
+			if budget.Action == database.BudgetActionBlock {
+				emitEvent("ai:budget:warning", BudgetWarning{JobID: jobID, Budget: budget, Unpriced: true})
+			}
+		}
+		return func() {}, nil
+	}
+
+	budgetReservations.Lock()
+	defer budgetReservations.Unlock()
+
+	for _, budget := range budgets {
+		spent, err := database.BudgetSpent(budget)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute spending for budget %d: %w", budget.ID, err)
+		}
+		reserved := budgetReservations.pending[budget.ID]
+		if spent+reserved+estimate <= budget.Limit {
+			continue
+		}
+
+		if budget.Action == database.BudgetActionBlock {
+			return nil, &BudgetExceededError{Budget: budget, Spent: spent, Reserved: reserved, Estimate: estimate}
+		}
+		emitEvent("ai:budget:warning", BudgetWarning{
+			JobID:    jobID,
+			Budget:   budget,
+			Spent:    spent,
+			Reserved: reserved,
+			Estimate: estimate,
+		})
+	}
+
+	for _, budget := range budgets {
+		budgetReservations.pending[budget.ID] += estimate
+	}
+	release := sync.OnceFunc(func() {
+		budgetReservations.Lock()
+		defer budgetReservations.Unlock()
+		for _, budget := range budgets {
+			if budgetReservations.pending[budget.ID] -= estimate; budgetReservations.pending[budget.ID] < 1e-9 {
+				delete(budgetReservations.pending, budget.ID)
+			}
+		}
+	})
+	return release, nil
+}
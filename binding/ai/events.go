@@ -0,0 +1,43 @@
+package ai
+
+import (
+	"fmt"
+
+	"visionflow/binding/app"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Stream event types sent on the "ai:stream:<requestID>" channel
+const (
+	StreamEventChunk = "chunk"
+	StreamEventDone  = "done"
+	StreamEventError = "error"
+)
+
+// StreamEvent is the payload emitted while a text stream is running
+type StreamEvent struct {
+	RequestID string                 `json:"requestId"`
+	Type      string                 `json:"type"`
+	Delta     string                 `json:"delta,omitempty"`
+	Content   string                 `json:"content,omitempty"`
+	Usage     map[string]interface{} `json:"usage,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+func streamEventName(requestID string) string {
+	return "ai:stream:" + requestID
+}
+
+// logf prints a problem that does not fail the call to the console
+func logf(format string, args ...interface{}) {
+	fmt.Printf(format+"\n", args...)
+}
+
+// emitEvent sends an event to the frontend. It is a no-op until the Wails runtime has started.
+func emitEvent(name string, data ...interface{}) {
+	if app.WailsContext == nil {
+		return
+	}
+	runtime.EventsEmit(*app.WailsContext, name, data...)
+}
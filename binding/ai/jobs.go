@@ -0,0 +1,126 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	aiservice "visionflow/service/ai"
+)
+
+// Job kinds tracked by the registry
+const (
+	JobKindText  = aiservice.UsageKindText
+	JobKindImage = aiservice.UsageKindImage
+	JobKindVideo = aiservice.UsageKindVideo
+	JobKindAudio = aiservice.UsageKindAudio
+
+	JobKindTranscription = aiservice.UsageKindTranscription
+)
+
+// ErrJobCancelled is returned by a generation that was stopped through CancelJob
+var ErrJobCancelled = errors.New("job was cancelled")
+
+// JobInfo describes a running generation job
+type JobInfo struct {
+	ID         string    `json:"id"`
+	Kind       string    `json:"kind"`
+	ProviderID int       `json:"providerId"`
+	Model      string    `json:"model"`
+	ProjectID  int       `json:"projectId,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+}
+
+type job struct {
+	info   JobInfo
+	cancel context.CancelCauseFunc
+}
+
+// jobRegistry keeps a cancel func for every generation that is in flight
+type jobRegistry struct {
+	mu   sync.Mutex
+	jobs map[string]*job
+	seq  atomic.Uint64
+}
+
+func newJobRegistry() *jobRegistry {
+	return &jobRegistry{jobs: make(map[string]*job)}
+}
+
+// start registers a job and returns its context. An empty id gets a generated one.
+// The returned finish func must be called once the job is over.
+func (r *jobRegistry) start(info JobInfo) (context.Context, JobInfo, func(), error) {
+	if info.ID == "" {
+		info.ID = fmt.Sprintf("job_%d_%d", time.Now().UnixNano(), r.seq.Add(1))
+	}
+	info.StartedAt = time.Now()
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+
+	r.mu.Lock()
+	if _, exists := r.jobs[info.ID]; exists {
+		r.mu.Unlock()
+		cancel(nil)
+		return nil, info, nil, fmt.Errorf("job %s is already running", info.ID)
+	}
+	r.jobs[info.ID] = &job{info: info, cancel: cancel}
+	r.mu.Unlock()
+
+	emitEvent("ai:job:started", info)
+
+	finish := func() {
+		r.mu.Lock()
+		delete(r.jobs, info.ID)
+		r.mu.Unlock()
+		cancel(nil)
+		emitEvent("ai:job:finished", info)
+	}
+	return ctx, info, finish, nil
+}
+
+func (r *jobRegistry) cancel(id string) error {
+	r.mu.Lock()
+	j, ok := r.jobs[id]
+	r.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no active job with id %s", id)
+	}
+	j.cancel(ErrJobCancelled)
+	return nil
+}
+
+func (r *jobRegistry) list() []JobInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	infos := make([]JobInfo, 0, len(r.jobs))
+	for _, j := range r.jobs {
+		infos = append(infos, j.info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].StartedAt.Before(infos[j].StartedAt)
+	})
+	return infos
+}
+
+// jobError reports a cancelled job with ErrJobCancelled instead of the client's wrapped context error
+func jobError(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, ErrJobCancelled) {
+		return cause
+	}
+	return err
+}
+
+// CancelJob stops a running generation. Its result, if any arrives, is discarded.
+func (s *Service) CancelJob(id string) error {
+	return s.jobs.cancel(id)
+}
+
+// ListActiveJobs lists the generations that are currently running
+func (s *Service) ListActiveJobs() []JobInfo {
+	return s.jobs.list()
+}
@@ -0,0 +1,102 @@
+package ai
+
+import (
+	"fmt"
+	"mime"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"visionflow/database"
+	aiservice "visionflow/service/ai"
+)
+
+// Directions of an UnsupportedModalityError
+const (
+	ModalityInput  = "input"
+	ModalityOutput = "output"
+)
+
+// inputModalities are checked in this order, so the error names the same modality every time
+var inputModalities = []string{"image", "video", "audio", "pdf"}
+
+// UnsupportedModalityError is returned before a call is made when the model does not declare a modality the request needs,
+// e.g. a video wired into a model that only reads images and would silently ignore it
+type UnsupportedModalityError struct {
+	Model     string
+	Direction string   // ModalityInput or ModalityOutput
+	Modality  string   // e.g. "video"
+	Supported []string // What the model declares for that direction
+}
+
+func (e *UnsupportedModalityError) Error() string {
+	return fmt.Sprintf("model %s does not support %s %s, it supports %s",
+		e.Model, e.Modality, e.Direction, strings.Join(e.Supported, ", "))
+}
+
+// attachments counts the attached files of a request per input modality
+type attachments map[string]int
+
+func (a attachments) add(images, videos, audios, documents []string) attachments {
+	a["image"] += len(images)
+	a["video"] += len(videos)
+	a["audio"] += len(audios)
+	a["pdf"] += len(documents)
+	return a
+}
+
+// textAttachments counts the attachments of the new turn and of the conversation before it
+func textAttachments(req TextRequest) attachments {
+	a := attachments{}.add(req.Images, req.Videos, req.Audios, req.Documents)
+	for _, msg := range req.Messages {
+		a.add(msg.Images, msg.Videos, msg.Audios, msg.Documents)
+	}
+	return a
+}
+
+// mediaAttachments counts the source of a transcription as a video or an audio file, by its extension
+func mediaAttachments(pathOrURL string) attachments {
+	if strings.HasPrefix(mime.TypeByExtension(filepath.Ext(aiservice.ContentName(pathOrURL))), "video/") {
+		return attachments{"video": 1}
+	}
+	return attachments{"audio": 1}
+}
+
+// checkModalities compares a request's attachments and expected output with the modalities the model declares
+// in models.dev or the user's overrides. Models nothing is known about are let through.
+// For chat requests the inputs are also limited to what the provider's client forwards.
+func checkModalities(providerID int, model string, inputs attachments, output string, chat bool) error {
+	config, err := database.GetModelProvider(providerID)
+	if err != nil {
+		return fmt.Errorf("failed to get config for provider id %d: %w", providerID, err)
+	}
+	if config == nil {
+		return fmt.Errorf("no configuration found for provider id %d. Please configure it in settings", providerID)
+	}
+
+	info := aiservice.DescribeModel(*config, model)
+	if len(info.Output) > 0 && !slices.Contains(info.Output, output) {
+		return &UnsupportedModalityError{Model: model, Direction: ModalityOutput, Modality: output, Supported: info.Output}
+	}
+
+	supported := info.Input
+	if chat {
+		forwarded := aiservice.ChatInputs(config.Type)
+		if len(supported) == 0 {
+			supported = forwarded
+		} else {
+			supported = slices.DeleteFunc(slices.Clone(supported), func(modality string) bool {
+				return slices.Contains(inputModalities, modality) && !slices.Contains(forwarded, modality)
+			})
+		}
+	}
+	if len(supported) == 0 {
+		return nil
+	}
+	for _, modality := range inputModalities {
+		if inputs[modality] > 0 && !slices.Contains(supported, modality) {
+			return &UnsupportedModalityError{Model: model, Direction: ModalityInput, Modality: modality, Supported: supported}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package ai
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMediaAttachments(t *testing.T) {
+	tests := []struct {
+		media string
+		want  attachments
+	}{
+		{"/assets/clip.mp4", attachments{"video": 1}},
+		{"https://example.com/files/clip.webm?token=abc", attachments{"video": 1}},
+		{"/assets/voice.mp3", attachments{"audio": 1}},
+		{"voice.wav", attachments{"audio": 1}},
+		{"recording", attachments{"audio": 1}},
+	}
+
+	for _, tt := range tests {
+		if got := mediaAttachments(tt.media); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("mediaAttachments(%q) = %v, want %v", tt.media, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"visionflow/database"
+	aiservice "visionflow/service/ai"
+)
+
+// plannerNodeTypes are the node types a plan may contain, groups are layout only and left to the user
+var plannerNodeTypes = []string{"text", "image", "video", "audio"}
+
+// plannerModelsPerType caps how many models of each output type are offered to the planner
+const plannerModelsPerType = 20
+
+const plannerSystemPrompt = `You design node-based generation workflows for VisionFlow.
+A workflow is a directed graph. Every node generates one output with its own model and prompt:
+- "text" nodes generate text, e.g. a script, shot descriptions or narration
+- "image" nodes generate one image
+- "video" nodes generate one video clip
+- "audio" nodes generate speech or sound
+An edge feeds the output of its source node into its target node. Text output is prepended to the
+target's prompt, images, videos and audio are attached as references. Prefer one node per shot or
+asset so each can be regenerated independently. Node prompts must be complete instructions that
+work together with the inputs arriving through edges.
+Pick every node's providerId and modelId from the available models listed for its type.`
+
+// WorkflowNodeData mirrors the data the canvas stores for a node
+type WorkflowNodeData struct {
+	Label      string `json:"label"`
+	Type       string `json:"type"`
+	Prompt     string `json:"prompt,omitempty"`
+	ModelID    string `json:"modelId,omitempty"`
+	ProviderID int    `json:"providerId,omitempty"`
+	ProjectID  int    `json:"projectId,omitempty"`
+}
+
+// WorkflowPosition is a node position on the canvas
+type WorkflowPosition struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// WorkflowNode is a canvas node in the shape stored in projects.workflow
+type WorkflowNode struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Position WorkflowPosition `json:"position"`
+	Data     WorkflowNodeData `json:"data"`
+}
+
+// WorkflowEdge is a canvas edge in the shape stored in projects.workflow
+type WorkflowEdge struct {
+	ID       string `json:"id"`
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	Animated bool   `json:"animated"`
+}
+
+// WorkflowPlan is a generated graph ready to be added to the canvas.
+// Warnings list the corrections made to the model's answer, e.g. replaced models or dropped edges.
+type WorkflowPlan struct {
+	Nodes    []WorkflowNode         `json:"nodes"`
+	Edges    []WorkflowEdge         `json:"edges"`
+	Warnings []string               `json:"warnings,omitempty"`
+	Usage    map[string]interface{} `json:"usage,omitempty"`
+}
+
+// plannedGraph is the structured answer requested from the planner model
+type plannedGraph struct {
+	Nodes []struct {
+		ID         string `json:"id"`
+		Type       string `json:"type"`
+		Label      string `json:"label"`
+		Prompt     string `json:"prompt"`
+		ProviderID int    `json:"providerId"`
+		ModelID    string `json:"modelId"`
+	} `json:"nodes"`
+	Edges []struct {
+		Source string `json:"source"`
+		Target string `json:"target"`
+	} `json:"edges"`
+}
+
+// plannerModel is a model the planner may assign to a node
+type plannerModel struct {
+	ProviderID int
+	ID         string
+}
+
+// PlanWorkflow asks the given text model to turn a natural-language request into a graph of
+// generation nodes. Node IDs continue after the ones in the project's saved workflow so the plan
+// can be added to the current canvas. The plan is returned, not saved.
+func (s *Service) PlanWorkflow(prompt string, projectID int, providerID int, model string) (*WorkflowPlan, error) {
+	if strings.TrimSpace(prompt) == "" {
+		return nil, errors.New("prompt is required")
+	}
+
+	ctx, _, finish, err := s.jobs.start(JobInfo{Kind: JobKindText, ProviderID: providerID, Model: model, ProjectID: projectID})
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	client, err := s.getClient(providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	available, err := plannerModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := TextRequest{
+		ProjectID:  projectID,
+		ProviderID: providerID,
+		Model:      model,
+		System:     plannerSystemPrompt + "\n\n" + describePlannerModels(available),
+		Prompt:     prompt,
+		ResponseFormat: &aiservice.ResponseFormat{
+			Type:   aiservice.ResponseFormatJSONSchema,
+			Name:   "workflow",
+			Schema: plannerSchema(),
+		},
+	}
+
+	resp, err := client.GenerateText(ctx, aiservice.TextGenerateRequest{
+		System:         req.System,
+		Prompt:         req.Prompt,
+		Model:          req.Model,
+		ResponseFormat: req.ResponseFormat,
+	})
+	if err != nil {
+		return nil, jobError(ctx, err)
+	}
+	recordTextUsage(req, resp)
+
+	var graph plannedGraph
+	if err := json.Unmarshal([]byte(resp.Content), &graph); err != nil {
+		return nil, fmt.Errorf("failed to decode workflow plan: %w", err)
+	}
+
+	firstID, err := nextWorkflowNodeID(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := buildWorkflowPlan(graph, available, projectID, firstID)
+	if err != nil {
+		return nil, err
+	}
+	plan.Usage = textUsage(resp)
+	return plan, nil
+}
+
+// plannerModels lists the configured models by the node type they can serve
+func plannerModels(ctx context.Context) (map[string][]plannerModel, error) {
+	providers, err := listProviderModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	available := map[string][]plannerModel{}
+	for _, provider := range providers {
+		for _, model := range provider.Models {
+			outputs := model.Output
+			if len(outputs) == 0 {
+				outputs = []string{"text"} // Models without capability data are assumed to be chat models
+			}
+			for _, output := range outputs {
+				available[output] = append(available[output], plannerModel{ProviderID: provider.ProviderID, ID: model.ID})
+			}
+		}
+	}
+	return available, nil
+}
+
+func describePlannerModels(available map[string][]plannerModel) string {
+	var b strings.Builder
+	b.WriteString("Available models (providerId: modelId):")
+	for _, nodeType := range plannerNodeTypes {
+		fmt.Fprintf(&b, "\n%s:", nodeType)
+		models := available[nodeType]
+		if len(models) == 0 {
+			b.WriteString(" none, do not use this node type")
+			continue
+		}
+		if len(models) > plannerModelsPerType {
+			models = models[:plannerModelsPerType]
+		}
+		for _, model := range models {
+			fmt.Fprintf(&b, "\n- %d: %s", model.ProviderID, model.ID)
+		}
+	}
+	return b.String()
+}
+
+func plannerSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"nodes": map[string]interface{}{
+				"type":     "array",
+				"minItems": 1,
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"id":         map[string]interface{}{"type": "string", "description": "Short unique ID referenced by edges"},
+						"type":       map[string]interface{}{"type": "string", "enum": plannerNodeTypes},
+						"label":      map[string]interface{}{"type": "string"},
+						"prompt":     map[string]interface{}{"type": "string"},
+						"providerId": map[string]interface{}{"type": "integer"},
+						"modelId":    map[string]interface{}{"type": "string"},
+					},
+					"required":             []string{"id", "type", "label", "prompt", "providerId", "modelId"},
+					"additionalProperties": false,
+				},
+			},
+			"edges": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"source": map[string]interface{}{"type": "string"},
+						"target": map[string]interface{}{"type": "string"},
+					},
+					"required":             []string{"source", "target"},
+					"additionalProperties": false,
+				},
+			},
+		},
+		"required":             []string{"nodes", "edges"},
+		"additionalProperties": false,
+	}
+}
+
+// nextWorkflowNodeID returns the first free node number of a project's saved workflow,
+// numbered the way the canvas numbers new nodes.
+func nextWorkflowNodeID(projectID int) (int, error) {
+	if projectID == 0 {
+		return 1, nil
+	}
+	project, err := database.GetProject(projectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load project %d: %w", projectID, err)
+	}
+	if project == nil || project.Workflow == "" {
+		return 1, nil
+	}
+
+	var workflow struct {
+		Nodes []struct {
+			ID string `json:"id"`
+		} `json:"nodes"`
+	}
+	if err := json.Unmarshal([]byte(project.Workflow), &workflow); err != nil {
+		logf("failed to parse workflow of project %d: %v", projectID, err)
+		return 1, nil
+	}
+
+	maxID := 0
+	for _, node := range workflow.Nodes {
+		if number, ok := workflowNodeNumber(node.ID); ok && number > maxID {
+			maxID = number
+		}
+	}
+	return maxID + 1, nil
+}
+
+// workflowNodeNumber reads N from a canvas node ID of the form "node-N", other IDs are not numbered by the canvas
+func workflowNodeNumber(id string) (int, bool) {
+	suffix, found := strings.CutPrefix(id, "node-")
+	if !found || suffix == "" || strings.TrimLeft(suffix, "0123456789") != "" {
+		return 0, false
+	}
+	number, err := strconv.Atoi(suffix)
+	return number, err == nil
+}
+
+// buildWorkflowPlan validates the planned graph and lays it out left to right by dependency depth.
+// Unusable models are replaced with the first model of the node's type, dangling, duplicate and
+// cycle-closing edges are dropped. Both are reported as warnings.
+func buildWorkflowPlan(graph plannedGraph, available map[string][]plannerModel, projectID int, firstID int) (*WorkflowPlan, error) {
+	if len(graph.Nodes) == 0 {
+		return nil, errors.New("workflow plan has no nodes")
+	}
+
+	plan := &WorkflowPlan{Edges: []WorkflowEdge{}}
+	ids := map[string]string{} // Planner ID to canvas ID
+	for i, planned := range graph.Nodes {
+		if planned.ID == "" {
+			return nil, fmt.Errorf("workflow plan node %d has no id", i)
+		}
+		if _, exists := ids[planned.ID]; exists {
+			return nil, fmt.Errorf("workflow plan has duplicate node id %q", planned.ID)
+		}
+
+		if !isPlannerNodeType(planned.Type) {
+			return nil, fmt.Errorf("workflow plan node %q has unsupported type %q", planned.ID, planned.Type)
+		}
+		models := available[planned.Type]
+		if len(models) == 0 {
+			return nil, fmt.Errorf("workflow plan needs a %s model but none is configured", planned.Type)
+		}
+
+		providerID, modelID := planned.ProviderID, planned.ModelID
+		if !hasPlannerModel(models, providerID, modelID) {
+			providerID, modelID = models[0].ProviderID, models[0].ID
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("node %q: model %q is not available for %s, using %s", planned.Label, planned.ModelID, planned.Type, modelID))
+		}
+
+		label := planned.Label
+		if label == "" {
+			label = planned.ID
+		}
+
+		id := fmt.Sprintf("node-%d", firstID+i)
+		ids[planned.ID] = id
+		plan.Nodes = append(plan.Nodes, WorkflowNode{
+			ID:   id,
+			Type: planned.Type,
+			Data: WorkflowNodeData{
+				Label:      label,
+				Type:       planned.Type,
+				Prompt:     planned.Prompt,
+				ModelID:    modelID,
+				ProviderID: providerID,
+				ProjectID:  projectID,
+			},
+		})
+	}
+
+	incoming := map[string][]string{}
+	seen := map[string]bool{}
+	for _, planned := range graph.Edges {
+		source, sourceOK := ids[planned.Source]
+		target, targetOK := ids[planned.Target]
+		switch {
+		case !sourceOK || !targetOK:
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("dropped edge %s -> %s to an unknown node", planned.Source, planned.Target))
+			continue
+		case source == target:
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("dropped edge from %s to itself", planned.Source))
+			continue
+		case seen[source+">"+target]:
+			continue
+		case reaches(incoming, source, target):
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("dropped edge %s -> %s that would create a cycle", planned.Source, planned.Target))
+			continue
+		}
+
+		seen[source+">"+target] = true
+		incoming[target] = append(incoming[target], source)
+		plan.Edges = append(plan.Edges, WorkflowEdge{
+			ID:     fmt.Sprintf("xy-edge__%s-%s", source, target),
+			Source: source,
+			Target: target,
+		})
+	}
+
+	layoutWorkflowPlan(plan.Nodes, incoming)
+	return plan, nil
+}
+
+func isPlannerNodeType(nodeType string) bool {
+	for _, t := range plannerNodeTypes {
+		if t == nodeType {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPlannerModel(models []plannerModel, providerID int, modelID string) bool {
+	for _, model := range models {
+		if model.ProviderID == providerID && model.ID == modelID {
+			return true
+		}
+	}
+	return false
+}
+
+// reaches reports whether from is upstream of node, in which case an edge node -> from closes a cycle
+func reaches(incoming map[string][]string, node, from string) bool {
+	visited := map[string]bool{}
+	stack := []string{node}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == from {
+			return true
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		stack = append(stack, incoming[current]...)
+	}
+	return false
+}
+
+// layoutWorkflowPlan places each node in the column of its longest input chain, top to bottom in plan order
+func layoutWorkflowPlan(nodes []WorkflowNode, incoming map[string][]string) {
+	const (
+		columnWidth = 400
+		rowHeight   = 320
+		margin      = 100
+	)
+
+	depth := map[string]int{}
+	var depthOf func(id string) int
+	depthOf = func(id string) int {
+		if d, ok := depth[id]; ok {
+			return d
+		}
+		d := 0
+		for _, source := range incoming[id] {
+			if sourceDepth := depthOf(source) + 1; sourceDepth > d {
+				d = sourceDepth
+			}
+		}
+		depth[id] = d
+		return d
+	}
+
+	rows := map[int]int{}
+	for i := range nodes {
+		column := depthOf(nodes[i].ID)
+		nodes[i].Position = WorkflowPosition{
+			X: float64(margin + column*columnWidth),
+			Y: float64(margin + rows[column]*rowHeight),
+		}
+		rows[column]++
+	}
+}
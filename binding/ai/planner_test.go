@@ -0,0 +1,134 @@
+package ai
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildWorkflowPlan(t *testing.T) {
+	available := map[string][]plannerModel{
+		"text":  {{ProviderID: 1, ID: "gpt-4o"}},
+		"image": {{ProviderID: 2, ID: "imagen-4.0-generate-001"}, {ProviderID: 1, ID: "gpt-image-1"}},
+	}
+	var graph plannedGraph
+	err := json.Unmarshal([]byte(`{
+		"nodes": [
+			{"id": "script", "type": "text", "label": "Script", "prompt": "Write", "providerId": 1, "modelId": "gpt-4o"},
+			{"id": "shot1", "type": "image", "label": "Shot 1", "prompt": "Draw", "providerId": 1, "modelId": "gpt-image-1"},
+			{"id": "shot2", "type": "image", "label": "", "prompt": "Draw", "providerId": 9, "modelId": "missing"}
+		],
+		"edges": [
+			{"source": "script", "target": "shot1"},
+			{"source": "script", "target": "shot1"},
+			{"source": "shot1", "target": "shot2"},
+			{"source": "shot2", "target": "script"},
+			{"source": "shot2", "target": "shot2"},
+			{"source": "script", "target": "nowhere"}
+		]
+	}`), &graph)
+	if err != nil {
+		t.Fatalf("invalid test graph: %v", err)
+	}
+
+	plan, err := buildWorkflowPlan(graph, available, 5, 7)
+	if err != nil {
+		t.Fatalf("buildWorkflowPlan: %v", err)
+	}
+
+	var ids []string
+	for _, node := range plan.Nodes {
+		ids = append(ids, node.ID)
+		if node.Data.ProjectID != 5 {
+			t.Errorf("node %s has project %d, want 5", node.ID, node.Data.ProjectID)
+		}
+	}
+	if want := []string{"node-7", "node-8", "node-9"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("node ids = %v, want %v", ids, want)
+	}
+
+	replaced := plan.Nodes[2].Data
+	if replaced.ProviderID != 2 || replaced.ModelID != "imagen-4.0-generate-001" || replaced.Label != "shot2" {
+		t.Errorf("unavailable model not replaced by the first image model: %+v", replaced)
+	}
+
+	var edges []string
+	for _, edge := range plan.Edges {
+		edges = append(edges, edge.Source+">"+edge.Target)
+	}
+	if want := []string{"node-7>node-8", "node-8>node-9"}; !reflect.DeepEqual(edges, want) {
+		t.Errorf("edges = %v, want %v", edges, want)
+	}
+
+	warnings := strings.Join(plan.Warnings, "\n")
+	for _, want := range []string{
+		`model "missing" is not available for image`,
+		"dropped edge shot2 -> script that would create a cycle",
+		"dropped edge from shot2 to itself",
+		"dropped edge script -> nowhere to an unknown node",
+	} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("warnings %q do not mention %q", plan.Warnings, want)
+		}
+	}
+	if len(plan.Warnings) != 4 {
+		t.Errorf("got %d warnings, want 4, the duplicate edge is dropped silently: %q", len(plan.Warnings), plan.Warnings)
+	}
+
+	// Columns follow the dependency depth
+	if !(plan.Nodes[0].Position.X < plan.Nodes[1].Position.X && plan.Nodes[1].Position.X < plan.Nodes[2].Position.X) {
+		t.Errorf("nodes are not laid out left to right by depth: %+v", plan.Nodes)
+	}
+}
+
+func TestBuildWorkflowPlanErrors(t *testing.T) {
+	available := map[string][]plannerModel{"text": {{ProviderID: 1, ID: "gpt-4o"}}}
+	tests := []struct {
+		name    string
+		graph   string
+		wantErr string
+	}{
+		{"no nodes", `{"nodes": [], "edges": []}`, "has no nodes"},
+		{"missing id", `{"nodes": [{"type": "text"}]}`, "node 0 has no id"},
+		{"duplicate id", `{"nodes": [{"id": "a", "type": "text"}, {"id": "a", "type": "text"}]}`, `duplicate node id "a"`},
+		{"unsupported type", `{"nodes": [{"id": "a", "type": "group"}]}`, `unsupported type "group"`},
+		{"no model for the type", `{"nodes": [{"id": "a", "type": "video"}]}`, "needs a video model"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var graph plannedGraph
+			if err := json.Unmarshal([]byte(tt.graph), &graph); err != nil {
+				t.Fatalf("invalid test graph: %v", err)
+			}
+			_, err := buildWorkflowPlan(graph, available, 0, 1)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorkflowNodeNumber(t *testing.T) {
+	tests := []struct {
+		id     string
+		want   int
+		wantOK bool
+	}{
+		{"node-1", 1, true},
+		{"node-42", 42, true},
+		{"node-1-2", 0, false},
+		{"node-", 0, false},
+		{"node-+3", 0, false},
+		{"group-7", 0, false},
+		{"7", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := workflowNodeNumber(tt.id)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("workflowNodeNumber(%q) = %d, %v, want %d, %v", tt.id, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
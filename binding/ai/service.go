@@ -3,6 +3,7 @@ package ai
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 
 	"visionflow/database"
 	aiservice "visionflow/service/ai"
@@ -11,15 +12,23 @@ import (
 )
 
 // Service provides AI methods for the frontend
-type Service struct{}
+type Service struct {
+	jobs *jobRegistry
+}
 
 // NewService creates a new AI Service
 func NewService() *Service {
-	return &Service{}
+	return &Service{
+		jobs: newJobRegistry(),
+	}
 }
 
 // TextRequest defines the parameters for text generation
 type TextRequest struct {
+	JobID       string                 `json:"jobId,omitempty"`
+	ProjectID   int                    `json:"projectId,omitempty"`
+	System      string                 `json:"system,omitempty"`
+	Messages    []aiservice.Message    `json:"messages,omitempty"`
 	Prompt      string                 `json:"prompt"`
 	Images      []string               `json:"images,omitempty"`
 	Videos      []string               `json:"videos,omitempty"`
@@ -30,39 +39,58 @@ type TextRequest struct {
 	Temperature *float64               `json:"temperature,omitempty"`
 	MaxTokens   *int                   `json:"maxTokens,omitempty"`
 	Options     map[string]interface{} `json:"options,omitempty"`
+	// ResponseFormat requests JSON output validated against a schema, e.g. for shot lists
+	ResponseFormat *aiservice.ResponseFormat `json:"responseFormat,omitempty"`
+	// Tools the model may call, the calls come back in Raw.toolCalls and are run by the caller
+	Tools      []aiservice.Tool `json:"tools,omitempty"`
+	ToolChoice string           `json:"toolChoice,omitempty"`
 }
 
 // ImageRequest defines the parameters for image generation
 type ImageRequest struct {
-	ProjectID  int                    `json:"projectId,omitempty"`
-	Prompt     string                 `json:"prompt"`
-	Images     []string               `json:"images,omitempty"`
-	Videos     []string               `json:"videos,omitempty"`
-	Audios     []string               `json:"audios,omitempty"`
-	Model      string                 `json:"model"`
-	ProviderID int                    `json:"providerId"`
-	Size       string                 `json:"size,omitempty"`
-	Quality    string                 `json:"quality,omitempty"`
-	Style      string                 `json:"style,omitempty"`
-	Options    map[string]interface{} `json:"options,omitempty"`
+	JobID            string                 `json:"jobId,omitempty"`
+	ProjectID        int                    `json:"projectId,omitempty"`
+	Prompt           string                 `json:"prompt"`
+	Images           []string               `json:"images,omitempty"`
+	Videos           []string               `json:"videos,omitempty"`
+	Audios           []string               `json:"audios,omitempty"`
+	MaskAssetID      int                    `json:"maskAssetId,omitempty"` // Image asset limiting an edit of Images[0] to its transparent region
+	Model            string                 `json:"model"`
+	ProviderID       int                    `json:"providerId"`
+	Size             string                 `json:"size,omitempty"`
+	Quality          string                 `json:"quality,omitempty"`
+	Style            string                 `json:"style,omitempty"`
+	N                int                    `json:"n,omitempty"`
+	AspectRatio      string                 `json:"aspectRatio,omitempty"`
+	NegativePrompt   string                 `json:"negativePrompt,omitempty"`
+	PersonGeneration string                 `json:"personGeneration,omitempty"`
+	Seed             *int                   `json:"seed,omitempty"`
+	Options          map[string]interface{} `json:"options,omitempty"`
 }
 
 // VideoRequest defines the parameters for video generation
 type VideoRequest struct {
-	ProjectID  int                    `json:"projectId,omitempty"`
-	Prompt     string                 `json:"prompt"`
-	Images     []string               `json:"images,omitempty"`
-	Videos     []string               `json:"videos,omitempty"`
-	Audios     []string               `json:"audios,omitempty"`
-	Model      string                 `json:"model"`
-	ProviderID int                    `json:"providerId"`
-	Duration   string                 `json:"duration,omitempty"`
-	Resolution string                 `json:"resolution,omitempty"`
-	Options    map[string]interface{} `json:"options,omitempty"`
+	JobID          string                 `json:"jobId,omitempty"`
+	ProjectID      int                    `json:"projectId,omitempty"`
+	Prompt         string                 `json:"prompt"`
+	Images         []string               `json:"images,omitempty"`
+	Videos         []string               `json:"videos,omitempty"`
+	Audios         []string               `json:"audios,omitempty"`
+	Model          string                 `json:"model"`
+	ProviderID     int                    `json:"providerId"`
+	Duration       string                 `json:"duration,omitempty"`
+	Resolution     string                 `json:"resolution,omitempty"`
+	AspectRatio    string                 `json:"aspectRatio,omitempty"`
+	NegativePrompt string                 `json:"negativePrompt,omitempty"`
+	Seed           *int                   `json:"seed,omitempty"`
+	GenerateAudio  *bool                  `json:"generateAudio,omitempty"`
+	N              int                    `json:"n,omitempty"`
+	Options        map[string]interface{} `json:"options,omitempty"`
 }
 
 // AudioRequest defines the parameters for audio generation
 type AudioRequest struct {
+	JobID      string                 `json:"jobId,omitempty"`
 	ProjectID  int                    `json:"projectId,omitempty"`
 	Prompt     string                 `json:"prompt"`
 	Images     []string               `json:"images,omitempty"`
@@ -75,11 +103,26 @@ type AudioRequest struct {
 	Options    map[string]interface{} `json:"options,omitempty"`
 }
 
+// TranscribeRequest defines the parameters for speech-to-text
+type TranscribeRequest struct {
+	JobID      string                 `json:"jobId,omitempty"`
+	ProjectID  int                    `json:"projectId,omitempty"`
+	Media      string                 `json:"media"` // Audio or video asset URL
+	Model      string                 `json:"model"`
+	ProviderID int                    `json:"providerId"`
+	Language   string                 `json:"language,omitempty"`
+	Prompt     string                 `json:"prompt,omitempty"`
+	Options    map[string]interface{} `json:"options,omitempty"`
+}
+
 // AIResponse defines the common response structure for AI requests
 type AIResponse struct {
-	Content string                 `json:"content"`
-	Usage   map[string]interface{} `json:"usage,omitempty"`
-	Raw     interface{}            `json:"raw,omitempty"`
+	JobID   string `json:"jobId,omitempty"`
+	Content string `json:"content"`
+	// Contents lists every output when a request produced several, Content is the first
+	Contents []string               `json:"contents,omitempty"`
+	Usage    map[string]interface{} `json:"usage,omitempty"`
+	Raw      interface{}            `json:"raw,omitempty"`
 }
 
 func (s *Service) getClient(providerID int) (aiservice.AIClient, error) {
@@ -96,114 +139,303 @@ func (s *Service) getClient(providerID int) (aiservice.AIClient, error) {
 
 // GenerateText generates text based on the prompt
 func (s *Service) GenerateText(req TextRequest) (*AIResponse, error) {
-	ctx := context.Background()
+	ctx, job, finish, err := s.jobs.start(JobInfo{ID: req.JobID, Kind: JobKindText, ProviderID: req.ProviderID, Model: req.Model, ProjectID: req.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	if err := checkModalities(req.ProviderID, req.Model, textAttachments(req), "text", true); err != nil {
+		return nil, err
+	}
+
 	client, err := s.getClient(req.ProviderID)
 	if err != nil {
 		return nil, err
 	}
 
 	aiReq := aiservice.TextGenerateRequest{
-		Prompt:      req.Prompt,
-		Images:      req.Images,
-		Videos:      req.Videos,
-		Audios:      req.Audios,
-		Documents:   req.Documents,
-		Model:       req.Model,
-		Temperature: req.Temperature,
-		MaxTokens:   req.MaxTokens,
-		Options:     req.Options,
+		System:         req.System,
+		Messages:       req.Messages,
+		Prompt:         req.Prompt,
+		Images:         req.Images,
+		Videos:         req.Videos,
+		Audios:         req.Audios,
+		Documents:      req.Documents,
+		Model:          req.Model,
+		Temperature:    req.Temperature,
+		MaxTokens:      req.MaxTokens,
+		Options:        req.Options,
+		ResponseFormat: req.ResponseFormat,
+		Tools:          req.Tools,
+		ToolChoice:     req.ToolChoice,
 	}
 
 	resp, err := client.GenerateText(ctx, aiReq)
 	if err != nil {
+		return nil, jobError(ctx, err)
+	}
+	recordTextUsage(req, resp)
+
+	return &AIResponse{
+		JobID:   job.ID,
+		Content: resp.Content,
+		Usage:   textUsage(resp),
+		Raw:     resp,
+	}, nil
+}
+
+// StreamText generates text like GenerateText but emits incremental chunks, the final usage
+// and errors as "ai:stream:<requestID>" events while the generation runs.
+// It still resolves with the complete response once the stream ends.
+func (s *Service) StreamText(requestID string, req TextRequest) (*AIResponse, error) {
+	if requestID == "" {
+		return nil, fmt.Errorf("request id is required for streaming")
+	}
+	eventName := streamEventName(requestID)
+
+	// The request ID doubles as the job ID so the stream can be cancelled with CancelJob
+	ctx, job, finish, err := s.jobs.start(JobInfo{ID: requestID, Kind: JobKindText, ProviderID: req.ProviderID, Model: req.Model, ProjectID: req.ProjectID})
+	if err != nil {
+		emitEvent(eventName, StreamEvent{RequestID: requestID, Type: StreamEventError, Error: err.Error()})
+		return nil, err
+	}
+	defer finish()
+
+	if err := checkModalities(req.ProviderID, req.Model, textAttachments(req), "text", true); err != nil {
+		emitEvent(eventName, StreamEvent{RequestID: requestID, Type: StreamEventError, Error: err.Error()})
 		return nil, err
 	}
 
+	client, err := s.getClient(req.ProviderID)
+	if err != nil {
+		emitEvent(eventName, StreamEvent{RequestID: requestID, Type: StreamEventError, Error: err.Error()})
+		return nil, err
+	}
+
+	aiReq := aiservice.TextGenerateRequest{
+		System:         req.System,
+		Messages:       req.Messages,
+		Prompt:         req.Prompt,
+		Images:         req.Images,
+		Videos:         req.Videos,
+		Audios:         req.Audios,
+		Documents:      req.Documents,
+		Model:          req.Model,
+		Temperature:    req.Temperature,
+		MaxTokens:      req.MaxTokens,
+		Options:        req.Options,
+		ResponseFormat: req.ResponseFormat,
+		Tools:          req.Tools,
+		ToolChoice:     req.ToolChoice,
+	}
+
+	resp, err := client.StreamText(ctx, aiReq, func(delta string) {
+		emitEvent(eventName, StreamEvent{RequestID: requestID, Type: StreamEventChunk, Delta: delta})
+	})
+	if err != nil {
+		err = jobError(ctx, err)
+		emitEvent(eventName, StreamEvent{RequestID: requestID, Type: StreamEventError, Error: err.Error()})
+		return nil, err
+	}
+
+	recordTextUsage(req, resp)
+
+	usage := textUsage(resp)
+	emitEvent(eventName, StreamEvent{RequestID: requestID, Type: StreamEventDone, Content: resp.Content, Usage: usage})
+
 	return &AIResponse{
+		JobID:   job.ID,
 		Content: resp.Content,
-		Usage: map[string]interface{}{
-			"promptTokens": resp.PromptTokens,
-			"outputTokens": resp.OutputTokens,
-			"totalTokens":  resp.TotalTokens,
-		},
-		Raw: resp,
+		Usage:   usage,
+		Raw:     resp,
 	}, nil
 }
 
+func textUsage(resp *aiservice.TextGenerateResponse) map[string]interface{} {
+	return map[string]interface{}{
+		"promptTokens": resp.PromptTokens,
+		"outputTokens": resp.OutputTokens,
+		"totalTokens":  resp.TotalTokens,
+	}
+}
+
 // GenerateImage generates an image based on the prompt
 func (s *Service) GenerateImage(req ImageRequest) (*AIResponse, error) {
-	ctx := context.Background()
-	client, err := s.getClient(req.ProviderID)
+	ctx, job, finish, err := s.jobs.start(JobInfo{ID: req.JobID, Kind: JobKindImage, ProviderID: req.ProviderID, Model: req.Model, ProjectID: req.ProjectID})
 	if err != nil {
 		return nil, err
 	}
+	defer finish()
 
-	aiReq := aiservice.ImageGenerateRequest{
-		Prompt:  req.Prompt,
-		Images:  req.Images,
-		Videos:  req.Videos,
-		Audios:  req.Audios,
-		Model:   req.Model,
-		Size:    req.Size,
-		Quality: req.Quality,
-		Style:   req.Style,
-		Options: req.Options,
+	if err := checkModalities(req.ProviderID, req.Model, attachments{}.add(req.Images, req.Videos, req.Audios, nil), "image", false); err != nil {
+		return nil, err
 	}
 
-	resp, err := client.GenerateImage(ctx, aiReq)
+	releaseBudget, err := checkBudgets(job.ID, req.ProjectID, req.ProviderID, req.Model, aiservice.UsageAmount{Images: max(req.N, 1)})
 	if err != nil {
 		return nil, err
 	}
+	defer releaseBudget()
 
-	content, err := s.processContent(req.ProjectID, resp.Data, resp.B64JSON, resp.URL, "image", ".png", database.AssetTypeImage)
+	client, err := s.getClient(req.ProviderID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &AIResponse{
-		Content: content,
+	mask, err := maskPath(req.MaskAssetID)
+	if err != nil {
+		return nil, err
+	}
+
+	aiReq := aiservice.ImageGenerateRequest{
+		Prompt:           req.Prompt,
+		Images:           req.Images,
+		Videos:           req.Videos,
+		Audios:           req.Audios,
+		Mask:             mask,
+		Model:            req.Model,
+		Size:             req.Size,
+		Quality:          req.Quality,
+		Style:            req.Style,
+		N:                req.N,
+		AspectRatio:      req.AspectRatio,
+		NegativePrompt:   req.NegativePrompt,
+		PersonGeneration: req.PersonGeneration,
+		Seed:             req.Seed,
+		Options:          req.Options,
+	}
+
+	resp, err := client.GenerateImage(ctx, aiReq)
+	if err != nil {
+		return nil, jobError(ctx, err)
+	}
+
+	if len(resp.Images) == 0 {
+		return nil, fmt.Errorf("model %s returned no image", responseModel(resp.Model, req.Model))
+	}
+	// Every returned candidate is billed, even if saving one of them fails below
+	aiservice.RecordUsage(aiservice.UsageEntry{
+		ProjectID:  req.ProjectID,
+		ProviderID: req.ProviderID,
+		Kind:       JobKindImage,
+		Model:      responseModel(resp.Model, req.Model),
+		Amount:     aiservice.UsageAmount{Images: len(resp.Images)},
+		Count:      len(resp.Images),
+	})
+	releaseBudget()
+
+	// The candidates are grouped under the job ID so they can be compared later
+	contents := make([]string, 0, len(resp.Images))
+	var saved []savedContent
+	for _, image := range resp.Images {
+		content, err := s.processContent(ctx, req.ProjectID, req.Prompt, job.ID, image.Data, image.B64JSON, image.URL, "image", ".png", database.AssetTypeImage)
+		if err != nil {
+			// A partial set would look like the complete result
+			discardContents(saved)
+			return nil, err
+		}
+		contents = append(contents, content.URL)
+		saved = append(saved, content)
+	}
+
+	result := &AIResponse{
+		JobID:   job.ID,
+		Content: contents[0],
 		Raw:     resp,
-	}, nil
+	}
+	if len(contents) > 1 {
+		result.Contents = contents
+	}
+	return result, nil
+}
+
+// maskPath resolves a mask asset to its file, an ID of 0 means no mask
+func maskPath(assetID int) (string, error) {
+	if assetID == 0 {
+		return "", nil
+	}
+	asset, err := database.GetAsset(assetID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get mask asset %d: %w", assetID, err)
+	}
+	if asset == nil {
+		return "", fmt.Errorf("mask asset %d not found", assetID)
+	}
+	if asset.Type != database.AssetTypeImage {
+		return "", fmt.Errorf("mask asset %d is %s, not an image", assetID, asset.Type)
+	}
+
+	assetsDir, err := storage.GetAssetsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(assetsDir, asset.Path), nil
 }
 
 // GenerateVideo generates a video based on the prompt
 func (s *Service) GenerateVideo(req VideoRequest) (*AIResponse, error) {
-	ctx := context.Background()
+	ctx, job, finish, err := s.jobs.start(JobInfo{ID: req.JobID, Kind: JobKindVideo, ProviderID: req.ProviderID, Model: req.Model, ProjectID: req.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	if err := checkModalities(req.ProviderID, req.Model, attachments{}.add(req.Images, req.Videos, req.Audios, nil), "video", false); err != nil {
+		return nil, err
+	}
+
+	seconds := aiservice.EstimateVideoSeconds(req.Model, req.Duration) * float64(max(req.N, 1))
+	releaseBudget, err := checkBudgets(job.ID, req.ProjectID, req.ProviderID, req.Model, aiservice.UsageAmount{VideoSeconds: seconds})
+	if err != nil {
+		return nil, err
+	}
+	defer releaseBudget()
+
 	client, err := s.getClient(req.ProviderID)
 	if err != nil {
 		return nil, err
 	}
 
-	aiReq := aiservice.VideoGenerateRequest{
-		Prompt:     req.Prompt,
-		Images:     req.Images,
-		Videos:     req.Videos,
-		Audios:     req.Audios,
-		Model:      req.Model,
-		Duration:   req.Duration,
-		Resolution: req.Resolution,
-		Options:    req.Options,
+	aiReq := req.toGenerateRequest()
+
+	// Providers with resumable jobs are persisted so a restart does not lose the generation
+	if jobClient, ok := client.(aiservice.VideoJobClient); ok {
+		contents, resp, err := s.runVideoJob(ctx, job, jobClient, req)
+		if err != nil {
+			return nil, err
+		}
+		return videoResponse(job.ID, contents, resp), nil
 	}
 
+	aiReq.OnProgress = videoProgressReporter(job.ID, req.ProjectID, job.StartedAt)
 	resp, err := client.GenerateVideo(ctx, aiReq)
 	if err != nil {
-		return nil, err
+		return nil, jobError(ctx, err)
 	}
 
-	content, err := s.processContent(req.ProjectID, resp.Data, "", resp.URL, "video", ".mp4", database.AssetTypeVideo)
+	recordVideoUsage(req, resp)
+	releaseBudget()
+	contents, _, err := s.saveVideos(ctx, req.ProjectID, req.Prompt, job.ID, resp)
 	if err != nil {
 		return nil, err
 	}
 
-	return &AIResponse{
-		Content: content,
-		Raw:     resp,
-	}, nil
+	return videoResponse(job.ID, contents, resp), nil
 }
 
 // GenerateAudio generates audio based on the prompt
 func (s *Service) GenerateAudio(req AudioRequest) (*AIResponse, error) {
-	ctx := context.Background()
+	ctx, job, finish, err := s.jobs.start(JobInfo{ID: req.JobID, Kind: JobKindAudio, ProviderID: req.ProviderID, Model: req.Model, ProjectID: req.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	if err := checkModalities(req.ProviderID, req.Model, attachments{}.add(req.Images, req.Videos, req.Audios, nil), "audio", false); err != nil {
+		return nil, err
+	}
+
 	client, err := s.getClient(req.ProviderID)
 	if err != nil {
 		return nil, err
@@ -222,45 +454,105 @@ func (s *Service) GenerateAudio(req AudioRequest) (*AIResponse, error) {
 
 	resp, err := client.GenerateAudio(ctx, aiReq)
 	if err != nil {
-		return nil, err
+		return nil, jobError(ctx, err)
 	}
 
 	// Usually audio is returned as bytes.
 	// We might want to base64 encode it for the frontend or return a Blob URL if we could.
 	// For now, let's assume valid JSON marshalling or handle it in specific response type
-	content, err := s.processContent(req.ProjectID, resp.Data, "", "", "audio", ".mp3", database.AssetTypeAudio)
+	ext := ".mp3"
+	if resp.Format != "" {
+		ext = "." + resp.Format
+	}
+	content, err := s.processContent(ctx, req.ProjectID, req.Prompt, "", resp.Data, "", "", "audio", ext, database.AssetTypeAudio)
 	if err != nil {
 		return nil, err
 	}
+	aiservice.RecordUsage(aiservice.UsageEntry{
+		ProjectID:  req.ProjectID,
+		ProviderID: req.ProviderID,
+		Kind:       JobKindAudio,
+		Model:      responseModel(resp.Model, req.Model),
+		Amount:     aiservice.UsageAmount{Characters: len([]rune(req.Prompt))},
+		Count:      1,
+	})
 
 	return &AIResponse{
-		Content: content,
+		JobID:   job.ID,
+		Content: content.URL,
 		Raw:     resp,
 	}, nil
 }
 
+// TranscribeAudio turns the speech in an audio or video asset into text.
+// Content holds the plain transcript so it can feed downstream prompts, Raw carries the timed segments.
+func (s *Service) TranscribeAudio(req TranscribeRequest) (*AIResponse, error) {
+	if req.Media == "" {
+		return nil, fmt.Errorf("media is required for transcription")
+	}
+
+	ctx, job, finish, err := s.jobs.start(JobInfo{ID: req.JobID, Kind: JobKindTranscription, ProviderID: req.ProviderID, Model: req.Model, ProjectID: req.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	if err := checkModalities(req.ProviderID, req.Model, mediaAttachments(req.Media), "text", false); err != nil {
+		return nil, err
+	}
+
+	client, err := s.getClient(req.ProviderID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Transcribe(ctx, aiservice.TranscribeRequest{
+		Media:    req.Media,
+		Model:    req.Model,
+		Language: req.Language,
+		Prompt:   req.Prompt,
+		Options:  req.Options,
+	})
+	if err != nil {
+		return nil, jobError(ctx, err)
+	}
+	aiservice.RecordUsage(aiservice.UsageEntry{
+		ProjectID:  req.ProjectID,
+		ProviderID: req.ProviderID,
+		Kind:       JobKindTranscription,
+		Model:      responseModel(resp.Model, req.Model),
+		Amount: aiservice.UsageAmount{
+			PromptTokens: resp.PromptTokens,
+			OutputTokens: resp.OutputTokens,
+			AudioSeconds: resp.Duration,
+		},
+		Count: 1,
+	})
+
+	return &AIResponse{
+		JobID:   job.ID,
+		Content: resp.Text,
+		Usage: map[string]interface{}{
+			"duration":     resp.Duration,
+			"promptTokens": resp.PromptTokens,
+			"outputTokens": resp.OutputTokens,
+		},
+		Raw: resp,
+	}, nil
+}
+
 // ListModels lists available models for a given provider ID. If providerId is nil, lists from all providers.
 func (s *Service) ListModels(providerId *int) ([]aiservice.Model, error) {
 	ctx := context.Background()
 	if providerId == nil {
-		configs, err := database.ListModelProviders()
+		providerModels, err := listProviderModels(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list providers: %w", err)
+			return nil, err
 		}
 
 		var allModels []aiservice.Model
-		for _, config := range configs {
-			client, err := aiservice.NewClient(config)
-			if err != nil {
-				fmt.Printf("failed to create client for %s: %v\n", config.Name, err)
-				continue
-			}
-			models, err := client.ListModels(ctx)
-			if err != nil {
-				fmt.Printf("failed to list models for %s: %v\n", config.Name, err)
-				continue
-			}
-			allModels = append(allModels, models...)
+		for _, models := range providerModels {
+			allModels = append(allModels, models.Models...)
 		}
 		return allModels, nil
 	}
@@ -281,7 +573,111 @@ func (s *Service) ListModels(providerId *int) ([]aiservice.Model, error) {
 	return client.ListModels(ctx)
 }
 
-func (s *Service) processContent(projectID int, data []byte, b64 string, url string, prefix string, ext string, assetType database.AssetType) (string, error) {
+// GetModelParameters lists the settings a provider's model accepts in a request's options,
+// for the node parameters panel to render controls from
+func (s *Service) GetModelParameters(providerID int, model string) ([]aiservice.Parameter, error) {
+	config, err := database.GetModelProvider(providerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config for provider id %d: %w", providerID, err)
+	}
+	if config == nil {
+		return nil, fmt.Errorf("no configuration found for provider id %d", providerID)
+	}
+
+	parameters := aiservice.ModelParameters(config.Type, model)
+	if parameters == nil {
+		parameters = []aiservice.Parameter{}
+	}
+	return parameters, nil
+}
+
+// GetModelInfo describes a provider's model from models.dev and the user's overrides,
+// so the frontend can check limits and pricing before running a node
+func (s *Service) GetModelInfo(providerID int, model string) (*aiservice.Model, error) {
+	config, err := database.GetModelProvider(providerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config for provider id %d: %w", providerID, err)
+	}
+	if config == nil {
+		return nil, fmt.Errorf("no configuration found for provider id %d", providerID)
+	}
+
+	info := aiservice.DescribeModel(*config, model)
+	return &info, nil
+}
+
+// RefreshCapabilities fetches the latest model capabilities and prices from models.dev
+func (s *Service) RefreshCapabilities() (aiservice.CapabilitiesStatus, error) {
+	status, err := aiservice.RefreshCapabilities(context.Background())
+	if err != nil {
+		return status, fmt.Errorf("failed to refresh model data: %w", err)
+	}
+	return status, nil
+}
+
+// CapabilitiesStatus reports where the model capabilities in use came from and when they were last refreshed
+func (s *Service) CapabilitiesStatus() aiservice.CapabilitiesStatus {
+	return aiservice.GetCapabilitiesStatus()
+}
+
+// providerModels groups the models of one configured provider
+type providerModels struct {
+	ProviderID int
+	Models     []aiservice.Model
+}
+
+// listProviderModels lists the models of every configured provider.
+// Providers that cannot be reached are logged and skipped.
+func listProviderModels(ctx context.Context) ([]providerModels, error) {
+	configs, err := database.ListModelProviders()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list providers: %w", err)
+	}
+
+	var result []providerModels
+	for _, config := range configs {
+		client, err := aiservice.NewClient(config)
+		if err != nil {
+			logf("failed to create client for %s: %v", config.Name, err)
+			continue
+		}
+		models, err := client.ListModels(ctx)
+		if err != nil {
+			logf("failed to list models for %s: %v", config.Name, err)
+			continue
+		}
+		result = append(result, providerModels{ProviderID: config.ID, Models: models})
+	}
+	return result, nil
+}
+
+// savedContent is a stored result, Asset is nil when no project was given or the asset record could not be created
+type savedContent struct {
+	URL      string
+	Filename string
+	Asset    *database.Asset
+}
+
+// discardContents deletes stored results together with their assets
+func discardContents(contents []savedContent) {
+	for _, content := range contents {
+		if content.Asset != nil && database.DeleteAsset(content.Asset.ID) == nil {
+			continue // Deleting the asset removed its file
+		}
+		if content.Filename != "" {
+			_ = storage.DeleteAssetContent(content.Filename)
+		}
+	}
+}
+
+// processContent stores a generated result and registers it as an asset together with its prompt.
+// Nothing is saved when ctx has been cancelled, so a stopped job never leaves a partial asset behind.
+// The result is empty when there was nothing to store.
+func (s *Service) processContent(ctx context.Context, projectID int, prompt string, generationID string, data []byte, b64 string, url string, prefix string, ext string, assetType database.AssetType) (savedContent, error) {
+	if err := ctx.Err(); err != nil {
+		return savedContent{}, jobError(ctx, err)
+	}
+
 	var filename string
 	var err error
 
@@ -290,30 +686,39 @@ func (s *Service) processContent(projectID int, data []byte, b64 string, url str
 	} else if b64 != "" {
 		filename, err = storage.SaveBase64Content(b64, prefix, ext)
 	} else if url != "" {
-		filename, err = storage.SaveURLContent(url, prefix, ext)
+		filename, err = storage.SaveURLContent(ctx, url, prefix, ext)
 	} else {
-		return "", nil
+		return savedContent{}, nil
 	}
 
 	if err != nil {
-		return "", fmt.Errorf("failed to save %s: %w", prefix, err)
+		return savedContent{}, jobError(ctx, fmt.Errorf("failed to save %s: %w", prefix, err))
+	}
+
+	// The job may have been cancelled while the file was being written
+	if err := ctx.Err(); err != nil {
+		_ = storage.DeleteAssetContent(filename)
+		return savedContent{}, jobError(ctx, err)
 	}
 
 	// Create asset in database if projectID is provided
+	var asset *database.Asset
 	if projectID > 0 {
-		_, err = database.CreateAsset(database.Asset{
-			ProjectID: projectID,
-			Type:      assetType,
-			Path:      filename,
+		asset, err = database.CreateAsset(database.Asset{
+			ProjectID:    projectID,
+			Type:         assetType,
+			Path:         filename,
+			Prompt:       prompt,
+			GenerationID: generationID,
 		})
 		if err != nil {
 			// Log error but don't fail the request (or maybe we should?)
 			// For now, let's log to console and move on, or return error?
 			// Better to log and continue, or return error if strict.
 			// Let's print for now as we don't have a logger struct here.
-			fmt.Printf("failed to create asset for project %d: %v\n", projectID, err)
+			logf("failed to create asset for project %d: %v", projectID, err)
 		}
 	}
 
-	return fileserver.GetFileUrl(filename), nil
+	return savedContent{URL: fileserver.GetFileUrl(filename), Filename: filename, Asset: asset}, nil
 }
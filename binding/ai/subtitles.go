@@ -0,0 +1,151 @@
+package ai
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"visionflow/database"
+	aiservice "visionflow/service/ai"
+	"visionflow/service/fileserver"
+	"visionflow/service/subtitle"
+	"visionflow/storage"
+)
+
+// SubtitleRequest defines the parameters for subtitle generation
+type SubtitleRequest struct {
+	JobID      string `json:"jobId,omitempty"`
+	AssetID    int    `json:"assetId"` // Audio or video asset to caption
+	Model      string `json:"model"`   // Transcription model, it must return timestamps (e.g. whisper-1)
+	ProviderID int    `json:"providerId"`
+	Language   string `json:"language,omitempty"`
+	Prompt     string `json:"prompt,omitempty"`
+}
+
+// SubtitleResult holds the subtitle assets created for a source asset
+type SubtitleResult struct {
+	JobID      string                        `json:"jobId,omitempty"`
+	SRT        *database.Asset               `json:"srt"`
+	VTT        *database.Asset               `json:"vtt"`
+	Transcript *aiservice.TranscribeResponse `json:"transcript"`
+}
+
+// GenerateSubtitles transcribes an audio or video asset and stores the captions as SRT and WebVTT
+// subtitle assets linked to it through SourceAssetID.
+func (s *Service) GenerateSubtitles(req SubtitleRequest) (*SubtitleResult, error) {
+	source, err := database.GetAsset(req.AssetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset %d: %w", req.AssetID, err)
+	}
+	if source == nil {
+		return nil, fmt.Errorf("asset %d not found", req.AssetID)
+	}
+	if source.Type != database.AssetTypeAudio && source.Type != database.AssetTypeVideo {
+		return nil, fmt.Errorf("asset %d is %s, subtitles need audio or video", req.AssetID, source.Type)
+	}
+
+	ctx, job, finish, err := s.jobs.start(JobInfo{ID: req.JobID, Kind: JobKindTranscription, ProviderID: req.ProviderID, Model: req.Model, ProjectID: source.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	if err := checkModalities(req.ProviderID, req.Model, mediaAttachments(source.Path), "text", false); err != nil {
+		return nil, err
+	}
+
+	client, err := s.getClient(req.ProviderID)
+	if err != nil {
+		return nil, err
+	}
+
+	assetsDir, err := storage.GetAssetsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Transcribe(ctx, aiservice.TranscribeRequest{
+		Media:    filepath.Join(assetsDir, source.Path),
+		Model:    req.Model,
+		Language: req.Language,
+		Prompt:   req.Prompt,
+	})
+	if err != nil {
+		return nil, jobError(ctx, err)
+	}
+	aiservice.RecordUsage(aiservice.UsageEntry{
+		ProjectID:  source.ProjectID,
+		ProviderID: req.ProviderID,
+		Kind:       JobKindTranscription,
+		Model:      responseModel(resp.Model, req.Model),
+		Amount: aiservice.UsageAmount{
+			PromptTokens: resp.PromptTokens,
+			OutputTokens: resp.OutputTokens,
+			AudioSeconds: resp.Duration,
+		},
+		Count: 1,
+	})
+
+	cues := subtitle.BuildCues(resp.Segments)
+	if len(cues) == 0 {
+		if resp.Text != "" {
+			return nil, fmt.Errorf("model %s returned no timestamps, choose a model with timed segments such as whisper-1", resp.Model)
+		}
+		return nil, fmt.Errorf("no speech found in asset %d", req.AssetID)
+	}
+
+	// The job may have been cancelled while transcribing
+	if err := ctx.Err(); err != nil {
+		return nil, jobError(ctx, err)
+	}
+
+	srt, err := saveSubtitle(source, resp.Text, []byte(subtitle.SRT(cues)), ".srt")
+	if err != nil {
+		return nil, err
+	}
+	// Keep the pair consistent, a lone SRT would look like a complete result
+	if err := ctx.Err(); err != nil {
+		_ = database.DeleteAsset(srt.ID)
+		return nil, jobError(ctx, err)
+	}
+	vtt, err := saveSubtitle(source, resp.Text, []byte(subtitle.VTT(cues)), ".vtt")
+	if err != nil {
+		_ = database.DeleteAsset(srt.ID)
+		return nil, err
+	}
+	// The job may have been cancelled while the captions were being written
+	if err := ctx.Err(); err != nil {
+		_ = database.DeleteAsset(srt.ID)
+		_ = database.DeleteAsset(vtt.ID)
+		return nil, jobError(ctx, err)
+	}
+
+	return &SubtitleResult{
+		JobID:      job.ID,
+		SRT:        srt,
+		VTT:        vtt,
+		Transcript: resp,
+	}, nil
+}
+
+// saveSubtitle stores a subtitle file and registers it as an asset derived from source.
+// The transcript becomes the asset's prompt so captions are found by search.
+func saveSubtitle(source *database.Asset, transcript string, data []byte, ext string) (*database.Asset, error) {
+	filename, err := storage.SaveAssetContent(data, "subtitle", ext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save subtitle: %w", err)
+	}
+
+	asset, err := database.CreateAsset(database.Asset{
+		ProjectID:     source.ProjectID,
+		Type:          database.AssetTypeSubtitle,
+		Path:          filename,
+		SourceAssetID: source.ID,
+		Prompt:        transcript,
+	})
+	if err != nil {
+		_ = storage.DeleteAssetContent(filename)
+		return nil, fmt.Errorf("failed to create subtitle asset: %w", err)
+	}
+	asset.URL = fileserver.GetFileUrl(asset.Path)
+	return asset, nil
+}
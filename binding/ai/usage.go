@@ -0,0 +1,39 @@
+package ai
+
+import (
+	aiservice "visionflow/service/ai"
+)
+
+// responseModel prefers the model reported by the provider, which may be more specific than the requested alias
+func responseModel(reported, requested string) string {
+	if reported != "" {
+		return reported
+	}
+	return requested
+}
+
+func recordTextUsage(req TextRequest, resp *aiservice.TextGenerateResponse) {
+	aiservice.RecordUsage(aiservice.UsageEntry{
+		ProjectID:  req.ProjectID,
+		ProviderID: req.ProviderID,
+		Kind:       JobKindText,
+		Model:      responseModel(resp.Model, req.Model),
+		Amount: aiservice.UsageAmount{
+			PromptTokens: resp.PromptTokens,
+			OutputTokens: resp.OutputTokens,
+		},
+		Count: 1,
+	})
+}
+
+func recordVideoUsage(req VideoRequest, resp *aiservice.VideoGenerateResponse) {
+	model := responseModel(resp.Model, req.Model)
+	aiservice.RecordUsage(aiservice.UsageEntry{
+		ProjectID:  req.ProjectID,
+		ProviderID: req.ProviderID,
+		Kind:       JobKindVideo,
+		Model:      model,
+		Amount:     aiservice.UsageAmount{VideoSeconds: aiservice.EstimateVideoSeconds(model, req.Duration) * float64(len(resp.Videos))},
+		Count:      len(resp.Videos),
+	})
+}
@@ -0,0 +1,285 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"visionflow/database"
+	aiservice "visionflow/service/ai"
+)
+
+// VideoJobEvent is emitted on "ai:job:completed", "ai:job:failed" and "ai:job:interrupted" for video jobs,
+// including the ones resumed after a restart, so the canvas can attach the result.
+type VideoJobEvent struct {
+	JobID     string   `json:"jobId"`
+	ProjectID int      `json:"projectId,omitempty"`
+	Content   string   `json:"content,omitempty"`
+	Contents  []string `json:"contents,omitempty"` // Every video of the job, Content is the first
+	AssetID   int      `json:"assetId,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// VideoJobProgress is emitted on "ai:job:progress" every time a video job is polled
+type VideoJobProgress struct {
+	JobID          string `json:"jobId"`
+	ProjectID      int    `json:"projectId,omitempty"`
+	Status         string `json:"status"`
+	Progress       *int   `json:"progress,omitempty"`
+	ElapsedSeconds int    `json:"elapsedSeconds"`
+}
+
+// videoProgressReporter returns an OnProgress callback that forwards provider status as "ai:job:progress" events
+func videoProgressReporter(jobID string, projectID int, startedAt time.Time) func(aiservice.VideoProgress) {
+	return func(progress aiservice.VideoProgress) {
+		emitEvent("ai:job:progress", VideoJobProgress{
+			JobID:          jobID,
+			ProjectID:      projectID,
+			Status:         progress.Status,
+			Progress:       progress.Progress,
+			ElapsedSeconds: int(time.Since(startedAt).Seconds()),
+		})
+	}
+}
+
+func (req VideoRequest) toGenerateRequest() aiservice.VideoGenerateRequest {
+	return aiservice.VideoGenerateRequest{
+		Prompt:         req.Prompt,
+		Images:         req.Images,
+		Videos:         req.Videos,
+		Audios:         req.Audios,
+		Model:          req.Model,
+		Duration:       req.Duration,
+		Resolution:     req.Resolution,
+		AspectRatio:    req.AspectRatio,
+		NegativePrompt: req.NegativePrompt,
+		Seed:           req.Seed,
+		GenerateAudio:  req.GenerateAudio,
+		N:              req.N,
+		Options:        req.Options,
+	}
+}
+
+// saveVideos stores every generated video as an asset grouped under generationID.
+// It returns the file URLs and the assets that could be created, in response order.
+func (s *Service) saveVideos(ctx context.Context, projectID int, prompt string, generationID string, resp *aiservice.VideoGenerateResponse) ([]string, []*database.Asset, error) {
+	if len(resp.Videos) == 0 {
+		return nil, nil, fmt.Errorf("model %s returned no video", resp.Model)
+	}
+
+	contents := make([]string, 0, len(resp.Videos))
+	var saved []savedContent
+	var assets []*database.Asset
+	for _, video := range resp.Videos {
+		content, err := s.processContent(ctx, projectID, prompt, generationID, video.Data, "", video.URL, "video", ".mp4", database.AssetTypeVideo)
+		if err != nil {
+			// A partial set would look like the complete result
+			discardContents(saved)
+			return nil, nil, err
+		}
+		contents = append(contents, content.URL)
+		saved = append(saved, content)
+		if content.Asset != nil {
+			assets = append(assets, content.Asset)
+		}
+	}
+	return contents, assets, nil
+}
+
+// videoResponse builds the binding response of a finished video generation
+func videoResponse(jobID string, contents []string, resp *aiservice.VideoGenerateResponse) *AIResponse {
+	result := &AIResponse{
+		JobID:   jobID,
+		Content: contents[0],
+		Raw:     resp,
+	}
+	if len(contents) > 1 {
+		result.Contents = contents
+	}
+	return result
+}
+
+// runVideoJob starts a provider side video job, records it in generation_jobs and waits for the result
+func (s *Service) runVideoJob(ctx context.Context, job JobInfo, client aiservice.VideoJobClient, req VideoRequest) ([]string, *aiservice.VideoGenerateResponse, error) {
+	requestJSON, err := json.Marshal(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode video request: %w", err)
+	}
+
+	record, err := database.CreateGenerationJob(database.GenerationJob{
+		JobID:      job.ID,
+		ProjectID:  req.ProjectID,
+		ProviderID: req.ProviderID,
+		Kind:       JobKindVideo,
+		Model:      req.Model,
+		Status:     database.GenerationJobPending,
+		Request:    string(requestJSON),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to record video job: %w", err)
+	}
+
+	providerJobID, err := client.StartVideoJob(ctx, req.toGenerateRequest())
+	if err != nil {
+		err = jobError(ctx, err)
+		s.failGenerationJob(*record, err)
+		return nil, nil, err
+	}
+
+	record.ProviderJobID = providerJobID
+	record.Status = database.GenerationJobRunning
+	if err := database.UpdateGenerationJob(*record); err != nil {
+		logf("failed to update video job %s: %v", job.ID, err)
+	}
+
+	return s.waitVideoJob(ctx, client, *record, req)
+}
+
+// waitVideoJob polls an already started provider job and stores its result as an asset of the job's project
+func (s *Service) waitVideoJob(ctx context.Context, client aiservice.VideoJobClient, record database.GenerationJob, req VideoRequest) ([]string, *aiservice.VideoGenerateResponse, error) {
+	aiReq := req.toGenerateRequest()
+	// Elapsed time counts from job creation so resumed jobs keep their original start
+	aiReq.OnProgress = videoProgressReporter(record.JobID, record.ProjectID, record.CreatedAt)
+
+	resp, err := client.WaitVideoJob(ctx, record.ProviderJobID, aiReq)
+	if err != nil {
+		err = jobError(ctx, err)
+		if errors.Is(err, ErrJobCancelled) || errors.Is(err, aiservice.ErrVideoJobFailed) {
+			s.failGenerationJob(record, err)
+		} else {
+			// Polling gave up, not the provider: the job keeps running there and is picked up again on the next start
+			s.interruptGenerationJob(record, err)
+		}
+		return nil, nil, err
+	}
+
+	// Resumed jobs carry the original request, so they are billed to the right project too.
+	// A job resumed because saving failed was billed already.
+	if !record.UsageRecorded {
+		recordVideoUsage(req, resp)
+		record.UsageRecorded = true
+		if err := database.UpdateGenerationJob(record); err != nil {
+			logf("failed to update video job %s: %v", record.JobID, err)
+		}
+	}
+
+	contents, assets, err := s.saveVideos(ctx, record.ProjectID, req.Prompt, record.JobID, resp)
+	if err != nil {
+		err = jobError(ctx, err)
+		if errors.Is(err, ErrJobCancelled) {
+			s.failGenerationJob(record, err)
+		} else {
+			// The video is paid for and stays downloadable, saving is tried again on resume
+			s.interruptGenerationJob(record, err)
+		}
+		return nil, nil, err
+	}
+
+	record.Status = database.GenerationJobCompleted
+	record.Error = ""
+	if len(assets) > 0 {
+		record.ResultAssetID = assets[0].ID
+	}
+	if err := database.UpdateGenerationJob(record); err != nil {
+		logf("failed to update video job %s: %v", record.JobID, err)
+	}
+
+	emitEvent("ai:job:completed", VideoJobEvent{
+		JobID:     record.JobID,
+		ProjectID: record.ProjectID,
+		Content:   contents[0],
+		Contents:  contents,
+		AssetID:   record.ResultAssetID,
+	})
+
+	return contents, resp, nil
+}
+
+func (s *Service) failGenerationJob(record database.GenerationJob, err error) {
+	record.Status = database.GenerationJobFailed
+	if errors.Is(err, ErrJobCancelled) {
+		record.Status = database.GenerationJobCancelled
+	}
+	record.Error = err.Error()
+	if updateErr := database.UpdateGenerationJob(record); updateErr != nil {
+		logf("failed to update video job %s: %v", record.JobID, updateErr)
+	}
+
+	emitEvent("ai:job:failed", VideoJobEvent{
+		JobID:     record.JobID,
+		ProjectID: record.ProjectID,
+		Error:     record.Error,
+	})
+}
+
+// interruptGenerationJob stores why the job stopped but leaves it running so ResumeGenerationJobs retries it,
+// "ai:job:interrupted" tells the UI it may offer to resume
+func (s *Service) interruptGenerationJob(record database.GenerationJob, err error) {
+	record.Error = err.Error()
+	if updateErr := database.UpdateGenerationJob(record); updateErr != nil {
+		logf("failed to update video job %s: %v", record.JobID, updateErr)
+	}
+
+	emitEvent("ai:job:interrupted", VideoJobEvent{
+		JobID:     record.JobID,
+		ProjectID: record.ProjectID,
+		Error:     record.Error,
+	})
+}
+
+// ResumeGenerationJobs resumes polling the video jobs that were still running when the app stopped
+// or were interrupted since, e.g. by a network failure. Results are attached to the originating project. It is safe to call more than once.
+func (s *Service) ResumeGenerationJobs() error {
+	records, err := database.ListUnfinishedGenerationJobs()
+	if err != nil {
+		return fmt.Errorf("failed to list unfinished generation jobs: %w", err)
+	}
+
+	for _, record := range records {
+		if record.ProviderJobID == "" {
+			// The provider never acknowledged the job, there is nothing to poll
+			s.failGenerationJob(record, errors.New("interrupted before the provider accepted the job"))
+			continue
+		}
+
+		var req VideoRequest
+		if err := json.Unmarshal([]byte(record.Request), &req); err != nil {
+			s.failGenerationJob(record, fmt.Errorf("failed to decode stored request: %w", err))
+			continue
+		}
+
+		client, err := s.getClient(record.ProviderID)
+		if err != nil {
+			s.failGenerationJob(record, err)
+			continue
+		}
+		jobClient, ok := client.(aiservice.VideoJobClient)
+		if !ok {
+			s.failGenerationJob(record, fmt.Errorf("provider %d cannot resume video jobs", record.ProviderID))
+			continue
+		}
+
+		ctx, _, finish, err := s.jobs.start(JobInfo{
+			ID:         record.JobID,
+			Kind:       JobKindVideo,
+			ProviderID: record.ProviderID,
+			Model:      record.Model,
+			ProjectID:  record.ProjectID,
+		})
+		if err != nil {
+			// Already being polled
+			continue
+		}
+
+		go func(record database.GenerationJob, req VideoRequest) {
+			defer finish()
+			if _, _, err := s.waitVideoJob(ctx, jobClient, record, req); err != nil {
+				logf("resumed video job %s failed: %v", record.JobID, err)
+			}
+		}(record, req)
+	}
+
+	return nil
+}
@@ -1,16 +1,20 @@
 package database
 
 import (
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 	"visionflow/binding/app"
 	db "visionflow/database"
 	"visionflow/service/fileserver"
+	"visionflow/service/search"
 	"visionflow/storage"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -75,11 +79,162 @@ func (s *Service) ListAssets(projectID int) ([]db.Asset, error) {
 	return assets, nil
 }
 
+// ListGenerationAssets lists the candidates saved by one multi-output generation, its ID is the job ID
+func (s *Service) ListGenerationAssets(generationID string) ([]db.Asset, error) {
+	assets, err := db.ListGenerationAssets(generationID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range assets {
+		assets[i].URL = fileserver.GetFileUrl(assets[i].Path)
+	}
+	return assets, nil
+}
+
+// searchResultLimit caps the number of assets returned by SearchAssets
+const searchResultLimit = 50
+
+// SearchAssets finds assets of all projects whose prompt or transcript is closest in meaning to the query.
+// It uses the embedding model configured on a model provider and indexes new assets on the way.
+func (s *Service) SearchAssets(query string) ([]db.AssetMatch, error) {
+	matches, err := search.SearchAssets(context.Background(), query, searchResultLimit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range matches {
+		matches[i].URL = fileserver.GetFileUrl(matches[i].Path)
+	}
+	return matches, nil
+}
+
 // DeleteAsset deletes an asset
 func (s *Service) DeleteAsset(id int) error {
 	return db.DeleteAsset(id)
 }
 
+// ListGenerationJobs lists long-running generation jobs for a project (pass 0 for all)
+func (s *Service) ListGenerationJobs(projectID int) ([]db.GenerationJob, error) {
+	return db.ListGenerationJobs(projectID)
+}
+
+// GetUsageByProject aggregates recorded usage and cost per project
+func (s *Service) GetUsageByProject() ([]db.UsageSummary, error) {
+	return db.UsageByProject()
+}
+
+// GetUsageByProvider aggregates recorded usage and cost per model provider (pass 0 for all projects)
+func (s *Service) GetUsageByProvider(projectID int) ([]db.UsageSummary, error) {
+	return db.UsageByProvider(projectID)
+}
+
+// GetUsageByDay aggregates recorded usage and cost per day over the last days days (pass 0 for all projects)
+func (s *Service) GetUsageByDay(projectID int, days int) ([]db.UsageSummary, error) {
+	return db.UsageByDay(projectID, days)
+}
+
+// SaveSpendingBudget saves or updates a spending budget
+func (s *Service) SaveSpendingBudget(budget db.SpendingBudget) (*db.SpendingBudget, error) {
+	switch budget.Scope {
+	case db.BudgetScopeGlobal:
+		budget.ScopeID = 0
+	case db.BudgetScopeProject, db.BudgetScopeProvider:
+		if budget.ScopeID == 0 {
+			return nil, fmt.Errorf("a %s budget needs a %s id", budget.Scope, budget.Scope)
+		}
+	default:
+		return nil, fmt.Errorf("unknown budget scope %q", budget.Scope)
+	}
+	if budget.Period != db.BudgetPeriodMonthly && budget.Period != db.BudgetPeriodTotal {
+		return nil, fmt.Errorf("unknown budget period %q", budget.Period)
+	}
+	if budget.Action != db.BudgetActionWarn && budget.Action != db.BudgetActionBlock {
+		return nil, fmt.Errorf("unknown budget action %q", budget.Action)
+	}
+	if budget.Limit <= 0 {
+		return nil, fmt.Errorf("budget limit must be positive")
+	}
+	return db.SaveSpendingBudget(budget)
+}
+
+// DeleteSpendingBudget deletes a spending budget
+func (s *Service) DeleteSpendingBudget(id int) error {
+	return db.DeleteSpendingBudget(id)
+}
+
+// ListSpendingBudgets lists all spending budgets with what has been spent in their current period
+func (s *Service) ListSpendingBudgets() ([]db.BudgetStatus, error) {
+	budgets, err := db.ListSpendingBudgets()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]db.BudgetStatus, 0, len(budgets))
+	for _, budget := range budgets {
+		spent, err := db.BudgetSpent(budget)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, db.BudgetStatus{SpendingBudget: budget, Spent: spent})
+	}
+	return statuses, nil
+}
+
+// knownModalities are the modalities models.dev and the providers use
+var knownModalities = []string{"text", "image", "audio", "video", "pdf", "embedding"}
+
+// SaveModelOverride creates or replaces the display name and modalities set for a provider's model
+func (s *Service) SaveModelOverride(override db.ModelOverride) (*db.ModelOverride, error) {
+	override.ModelID = strings.TrimSpace(override.ModelID)
+	override.DisplayName = strings.TrimSpace(override.DisplayName)
+	if override.ModelID == "" {
+		return nil, fmt.Errorf("model id is required")
+	}
+	provider, err := db.GetModelProvider(override.ProviderID)
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("no configuration found for provider id %d", override.ProviderID)
+	}
+
+	if override.Input, err = normalizeModalities(override.Input); err != nil {
+		return nil, fmt.Errorf("input: %w", err)
+	}
+	if override.Output, err = normalizeModalities(override.Output); err != nil {
+		return nil, fmt.Errorf("output: %w", err)
+	}
+	if override.DisplayName == "" && len(override.Input) == 0 && len(override.Output) == 0 {
+		return nil, fmt.Errorf("an override needs a display name or modalities")
+	}
+	return db.SaveModelOverride(override)
+}
+
+// normalizeModalities lowercases and deduplicates modalities, rejecting unknown ones
+func normalizeModalities(modalities db.Modalities) (db.Modalities, error) {
+	var normalized db.Modalities
+	for _, modality := range modalities {
+		modality = strings.ToLower(strings.TrimSpace(modality))
+		if modality == "" || slices.Contains(normalized, modality) {
+			continue
+		}
+		if !slices.Contains(knownModalities, modality) {
+			return nil, fmt.Errorf("unknown modality %q, use one of %s", modality, strings.Join(knownModalities, ", "))
+		}
+		normalized = append(normalized, modality)
+	}
+	return normalized, nil
+}
+
+// DeleteModelOverride deletes a model override
+func (s *Service) DeleteModelOverride(id int) error {
+	return db.DeleteModelOverride(id)
+}
+
+// ListModelOverrides lists the model overrides of a provider, or of all providers when providerID is 0
+func (s *Service) ListModelOverrides(providerID int) ([]db.ModelOverride, error) {
+	return db.ListModelOverrides(providerID)
+}
+
 // CreateAssetFromFile saves a file provided as bytes as an asset
 func (s *Service) CreateAssetFromFile(name string, data []byte) (*db.Asset, error) {
 	// Calculate MD5 hash
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"fmt"
 	"log"
 
 	"visionflow/storage"
@@ -30,6 +31,8 @@ func InitDB() error {
 		type TEXT NOT NULL,
 		api_key TEXT NOT NULL,
 		base_url TEXT DEFAULT '',
+		retry_max_attempts INTEGER DEFAULT 0,
+		retry_budget_seconds INTEGER DEFAULT 0,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
@@ -59,6 +62,78 @@ func InitDB() error {
 
 	CREATE INDEX IF NOT EXISTS idx_assets_md5 ON assets(md5);
 
+	CREATE TABLE IF NOT EXISTS asset_embeddings (
+		asset_id INTEGER NOT NULL,
+		provider_id INTEGER NOT NULL,
+		model TEXT NOT NULL,
+		content_hash TEXT NOT NULL,
+		vector BLOB NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY(asset_id, model),
+		FOREIGN KEY(asset_id) REFERENCES assets(id)
+	);
+
+	CREATE TABLE IF NOT EXISTS generation_jobs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		job_id TEXT NOT NULL,
+		project_id INTEGER DEFAULT 0,
+		provider_id INTEGER NOT NULL,
+		provider_job_id TEXT DEFAULT '',
+		kind TEXT NOT NULL,
+		model TEXT DEFAULT '',
+		status TEXT NOT NULL,
+		request TEXT DEFAULT '',
+		result_asset_id INTEGER DEFAULT 0,
+		error TEXT DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_generation_jobs_status ON generation_jobs(status);
+
+	CREATE TABLE IF NOT EXISTS usage_records (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		project_id INTEGER DEFAULT 0,
+		provider_id INTEGER DEFAULT 0,
+		provider_type TEXT DEFAULT '',
+		model TEXT DEFAULT '',
+		kind TEXT NOT NULL,
+		prompt_tokens INTEGER DEFAULT 0,
+		output_tokens INTEGER DEFAULT 0,
+		total_tokens INTEGER DEFAULT 0,
+		count INTEGER DEFAULT 0,
+		cost REAL DEFAULT 0,
+		priced BOOLEAN DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_usage_records_project ON usage_records(project_id);
+	CREATE INDEX IF NOT EXISTS idx_usage_records_created_at ON usage_records(created_at);
+
+	CREATE TABLE IF NOT EXISTS spending_budgets (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		scope TEXT NOT NULL,
+		scope_id INTEGER DEFAULT 0,
+		period TEXT NOT NULL,
+		limit_usd REAL NOT NULL,
+		action TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS model_overrides (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		provider_id INTEGER NOT NULL,
+		model_id TEXT NOT NULL COLLATE NOCASE,
+		display_name TEXT DEFAULT '',
+		input TEXT DEFAULT '',
+		output TEXT DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(provider_id, model_id),
+		FOREIGN KEY(provider_id) REFERENCES model_providers(id)
+	);
+
 	CREATE TABLE IF NOT EXISTS user_preferences (
 		key TEXT PRIMARY KEY,
 		value TEXT NOT NULL,
@@ -71,6 +146,44 @@ func InitDB() error {
 		return err
 	}
 
+	if err := migrateColumns(); err != nil {
+		return err
+	}
+
 	log.Println("Database initialized successfully")
 	return nil
 }
+
+// columnMigrations lists columns added after their table was first created.
+// CREATE TABLE IF NOT EXISTS leaves existing databases untouched, so they are added here.
+var columnMigrations = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{"model_providers", "retry_max_attempts", "INTEGER DEFAULT 0"},
+	{"model_providers", "retry_budget_seconds", "INTEGER DEFAULT 0"},
+	{"assets", "source_asset_id", "INTEGER DEFAULT 0"},
+	{"assets", "prompt", "TEXT DEFAULT ''"},
+	{"model_providers", "embedding_model", "TEXT DEFAULT ''"},
+	{"assets", "generation_id", "TEXT DEFAULT ''"},
+	{"generation_jobs", "usage_recorded", "BOOLEAN DEFAULT 0"},
+}
+
+func migrateColumns() error {
+	for _, m := range columnMigrations {
+		var count int
+		err := DB.Get(&count, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", m.table, m.column)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+		if _, err := DB.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", m.table, m.column, m.definition)); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", m.table, m.column, err)
+		}
+		log.Printf("Added column %s.%s", m.table, m.column)
+	}
+	return nil
+}
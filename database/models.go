@@ -1,6 +1,11 @@
 package database
 
-import "time"
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+)
 
 type AIProvider string
 
@@ -8,17 +13,22 @@ const (
 	ProviderGemini AIProvider = "gemini"
 	ProviderOpenAI AIProvider = "openai"
 	ProviderClaude AIProvider = "claude"
+	ProviderOllama AIProvider = "ollama"
+	ProviderMock   AIProvider = "mock" // Offline provider for demos and local testing
 )
 
 // ModelProvider represents an AI model provider configuration
 type ModelProvider struct {
-	ID        int        `db:"id" json:"id"`
-	Name      string     `db:"name" json:"name"`
-	Type      AIProvider `db:"type" json:"type"`
-	APIKey    string     `db:"api_key" json:"apiKey"`
-	BaseURL   string     `db:"base_url" json:"baseUrl"`
-	CreatedAt time.Time  `db:"created_at" json:"createdAt"`
-	UpdatedAt time.Time  `db:"updated_at" json:"updatedAt"`
+	ID                 int        `db:"id" json:"id"`
+	Name               string     `db:"name" json:"name"`
+	Type               AIProvider `db:"type" json:"type"`
+	APIKey             string     `db:"api_key" json:"apiKey"`
+	BaseURL            string     `db:"base_url" json:"baseUrl"`
+	RetryMaxAttempts   int        `db:"retry_max_attempts" json:"retryMaxAttempts"`     // 0 keeps the provider default
+	RetryBudgetSeconds int        `db:"retry_budget_seconds" json:"retryBudgetSeconds"` // 0 keeps the provider default
+	EmbeddingModel     string     `db:"embedding_model" json:"embeddingModel"`          // Model used to index assets for search, empty disables it
+	CreatedAt          time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt          time.Time  `db:"updated_at" json:"updatedAt"`
 }
 
 // Project represents a project entity
@@ -35,12 +45,13 @@ type Project struct {
 type AssetType string
 
 const (
-	AssetTypeImage AssetType = "image"
-	AssetTypeVideo AssetType = "video"
-	AssetTypeAudio AssetType = "audio"
+	AssetTypeImage    AssetType = "image"
+	AssetTypeVideo    AssetType = "video"
+	AssetTypeAudio    AssetType = "audio"
+	AssetTypeSubtitle AssetType = "subtitle"
 )
 
-// Asset represents a stored item (image/video/audio) associated with a project/workflow
+// Asset represents a stored item (image/video/audio/subtitle) associated with a project/workflow
 type Asset struct {
 	ID             int       `db:"id" json:"id"`
 	ProjectID      int       `db:"project_id" json:"projectId"`
@@ -49,10 +60,155 @@ type Asset struct {
 	URL            string    `db:"-" json:"url"`
 	IsUserProvided bool      `db:"is_user_provided" json:"isUserProvided"`
 	MD5            string    `db:"md5" json:"md5"`
+	Prompt         string    `db:"prompt" json:"prompt,omitempty"`                 // Prompt or transcript the asset was generated from, used for search
+	SourceAssetID  int       `db:"source_asset_id" json:"sourceAssetId,omitempty"` // Asset this one was derived from, e.g. the video of a subtitle
+	GenerationID   string    `db:"generation_id" json:"generationId,omitempty"`    // Shared by the candidates of one multi-output request
 	CreatedAt      time.Time `db:"created_at" json:"createdAt"`
 	UpdatedAt      time.Time `db:"updated_at" json:"updatedAt"`
 }
 
+type GenerationJobStatus string
+
+const (
+	GenerationJobPending   GenerationJobStatus = "pending"
+	GenerationJobRunning   GenerationJobStatus = "running"
+	GenerationJobCompleted GenerationJobStatus = "completed"
+	GenerationJobFailed    GenerationJobStatus = "failed"
+	GenerationJobCancelled GenerationJobStatus = "cancelled"
+)
+
+// GenerationJob represents a long-running provider job (e.g. a video generation) that survives restarts
+type GenerationJob struct {
+	ID            int                 `db:"id" json:"id"`
+	JobID         string              `db:"job_id" json:"jobId"`
+	ProjectID     int                 `db:"project_id" json:"projectId"`
+	ProviderID    int                 `db:"provider_id" json:"providerId"`
+	ProviderJobID string              `db:"provider_job_id" json:"providerJobId"`
+	Kind          string              `db:"kind" json:"kind"`
+	Model         string              `db:"model" json:"model"`
+	Status        GenerationJobStatus `db:"status" json:"status"`
+	Request       string              `db:"request" json:"request"`
+	ResultAssetID int                 `db:"result_asset_id" json:"resultAssetId"`
+	Error         string              `db:"error" json:"error"`
+	UsageRecorded bool                `db:"usage_recorded" json:"usageRecorded"` // The finished result was billed, a retried save must not bill it again
+	CreatedAt     time.Time           `db:"created_at" json:"createdAt"`
+	UpdatedAt     time.Time           `db:"updated_at" json:"updatedAt"`
+}
+
+// UsageRecord is a ledger entry for a single successful generation call
+type UsageRecord struct {
+	ID           int       `db:"id" json:"id"`
+	ProjectID    int       `db:"project_id" json:"projectId"`
+	ProviderID   int       `db:"provider_id" json:"providerId"`
+	ProviderType string    `db:"provider_type" json:"providerType"`
+	Model        string    `db:"model" json:"model"`
+	Kind         string    `db:"kind" json:"kind"`
+	PromptTokens int       `db:"prompt_tokens" json:"promptTokens"`
+	OutputTokens int       `db:"output_tokens" json:"outputTokens"`
+	TotalTokens  int       `db:"total_tokens" json:"totalTokens"`
+	Count        int       `db:"count" json:"count"`   // Outputs produced: responses, images, videos, audio clips, transcripts or embeddings
+	Cost         float64   `db:"cost" json:"cost"`     // USD, 0 when the model has no known pricing
+	Priced       bool      `db:"priced" json:"priced"` // Whether Cost comes from known pricing
+	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+}
+
+// UsageSummary aggregates usage records grouped by project, provider or day
+type UsageSummary struct {
+	Key          string  `db:"key" json:"key"`
+	Label        string  `db:"label" json:"label"`
+	Calls        int     `db:"calls" json:"calls"`
+	PromptTokens int     `db:"prompt_tokens" json:"promptTokens"`
+	OutputTokens int     `db:"output_tokens" json:"outputTokens"`
+	TotalTokens  int     `db:"total_tokens" json:"totalTokens"`
+	Count        int     `db:"count" json:"count"` // Outputs produced, see UsageRecord.Count
+	Cost         float64 `db:"cost" json:"cost"`
+	Unpriced     int     `db:"unpriced" json:"unpriced"` // Calls whose cost could not be computed
+}
+
+type BudgetScope string
+
+const (
+	BudgetScopeGlobal   BudgetScope = "global"   // All spending
+	BudgetScopeProject  BudgetScope = "project"  // Spending of one project
+	BudgetScopeProvider BudgetScope = "provider" // Spending through one model provider
+)
+
+type BudgetPeriod string
+
+const (
+	BudgetPeriodMonthly BudgetPeriod = "monthly" // Resets on the first day of every month (UTC)
+	BudgetPeriodTotal   BudgetPeriod = "total"   // Never resets
+)
+
+type BudgetAction string
+
+const (
+	BudgetActionWarn  BudgetAction = "warn"  // Run the call but notify the frontend
+	BudgetActionBlock BudgetAction = "block" // Refuse the call
+)
+
+// SpendingBudget caps what may be spent within a scope over a period
+type SpendingBudget struct {
+	ID        int          `db:"id" json:"id"`
+	Scope     BudgetScope  `db:"scope" json:"scope"`
+	ScopeID   int          `db:"scope_id" json:"scopeId"` // Project or provider ID, 0 for the global scope
+	Period    BudgetPeriod `db:"period" json:"period"`
+	Limit     float64      `db:"limit_usd" json:"limit"` // USD
+	Action    BudgetAction `db:"action" json:"action"`
+	CreatedAt time.Time    `db:"created_at" json:"createdAt"`
+	UpdatedAt time.Time    `db:"updated_at" json:"updatedAt"`
+}
+
+// BudgetStatus is a spending budget together with what has been spent in its current period
+type BudgetStatus struct {
+	SpendingBudget
+	Spent float64 `json:"spent"`
+}
+
+// Modalities is a list of input or output modalities such as "text" or "image",
+// stored as a comma separated column
+type Modalities []string
+
+// Value implements driver.Valuer
+func (m Modalities) Value() (driver.Value, error) {
+	return strings.Join(m, ","), nil
+}
+
+// Scan implements sql.Scanner, an empty column scans to nil
+func (m *Modalities) Scan(src interface{}) error {
+	var value string
+	switch v := src.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Modalities", src)
+	}
+
+	*m = nil
+	for _, modality := range strings.Split(value, ",") {
+		if modality = strings.TrimSpace(modality); modality != "" {
+			*m = append(*m, modality)
+		}
+	}
+	return nil
+}
+
+// ModelOverride replaces what models.dev says about a provider's model, for proxy hosted,
+// fine-tuned or otherwise unknown models. Empty fields keep the models.dev data.
+type ModelOverride struct {
+	ID          int        `db:"id" json:"id"`
+	ProviderID  int        `db:"provider_id" json:"providerId"`
+	ModelID     string     `db:"model_id" json:"modelId"`
+	DisplayName string     `db:"display_name" json:"displayName"`
+	Input       Modalities `db:"input" json:"input"`
+	Output      Modalities `db:"output" json:"output"`
+	CreatedAt   time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updatedAt"`
+}
+
 // UserPreference represents a user preference key-value pair
 type UserPreference struct {
 	Key       string    `db:"key" json:"key"`
@@ -60,3 +216,19 @@ type UserPreference struct {
 	CreatedAt time.Time `db:"created_at" json:"createdAt"`
 	UpdatedAt time.Time `db:"updated_at" json:"updatedAt"`
 }
+
+// AssetEmbedding is the search vector of an asset's prompt for one embedding model
+type AssetEmbedding struct {
+	AssetID     int       `db:"asset_id" json:"assetId"`
+	ProviderID  int       `db:"provider_id" json:"providerId"`
+	Model       string    `db:"model" json:"model"`
+	ContentHash string    `db:"content_hash" json:"contentHash"` // Hash of the embedded text, a changed prompt needs a new vector
+	Vector      []byte    `db:"vector" json:"-"`                 // Little-endian float32 values
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+}
+
+// AssetMatch is an asset found by semantic search
+type AssetMatch struct {
+	Asset
+	Score float64 `json:"score"` // Cosine similarity to the query, higher is closer
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	"visionflow/storage"
 )
 
@@ -38,8 +39,8 @@ func SaveModelProvider(config ModelProvider) error {
 	if config.ID == 0 {
 		// Insert
 		_, err := DB.NamedExec(`
-            INSERT INTO model_providers (name, type, api_key, base_url, created_at, updated_at)
-            VALUES (:name, :type, :api_key, :base_url, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+            INSERT INTO model_providers (name, type, api_key, base_url, retry_max_attempts, retry_budget_seconds, embedding_model, created_at, updated_at)
+            VALUES (:name, :type, :api_key, :base_url, :retry_max_attempts, :retry_budget_seconds, :embedding_model, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
         `, config)
 		return err
 	}
@@ -47,7 +48,9 @@ func SaveModelProvider(config ModelProvider) error {
 	// Update
 	_, err := DB.NamedExec(`
 		UPDATE model_providers 
-		SET name = :name, type = :type, api_key = :api_key, base_url = :base_url, updated_at = CURRENT_TIMESTAMP
+		SET name = :name, type = :type, api_key = :api_key, base_url = :base_url,
+			retry_max_attempts = :retry_max_attempts, retry_budget_seconds = :retry_budget_seconds,
+			embedding_model = :embedding_model, updated_at = CURRENT_TIMESTAMP
 		WHERE id = :id
 	`, config)
 	return err
@@ -56,6 +59,9 @@ func SaveModelProvider(config ModelProvider) error {
 // DeleteModelProvider deletes a model provider configuration
 func DeleteModelProvider(id int) error {
 	print(id)
+	if _, err := DB.Exec("DELETE FROM model_overrides WHERE provider_id = ?", id); err != nil {
+		return err
+	}
 	_, err := DB.Exec("DELETE FROM model_providers WHERE id = ?", id)
 	return err
 }
@@ -134,8 +140,8 @@ func ListProjects() ([]Project, error) {
 func CreateAsset(asset Asset) (*Asset, error) {
 	// Insert
 	result, err := DB.NamedExec(`
-        INSERT INTO assets (project_id, type, path, is_user_provided, md5, created_at, updated_at)
-        VALUES (:project_id, :type, :path, :is_user_provided, :md5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+        INSERT INTO assets (project_id, type, path, is_user_provided, md5, source_asset_id, prompt, generation_id, created_at, updated_at)
+        VALUES (:project_id, :type, :path, :is_user_provided, :md5, :source_asset_id, :prompt, :generation_id, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
     `, asset)
 	if err != nil {
 		return nil, err
@@ -192,6 +198,19 @@ func ListAssets(projectID int) ([]Asset, error) {
 	return assets, nil
 }
 
+// ListGenerationAssets lists the assets produced by one generation request in creation order
+func ListGenerationAssets(generationID string) ([]Asset, error) {
+	if generationID == "" {
+		return nil, nil
+	}
+	var assets []Asset
+	err := DB.Select(&assets, "SELECT * FROM assets WHERE generation_id = ? ORDER BY id", generationID)
+	if err != nil {
+		return nil, err
+	}
+	return assets, nil
+}
+
 // DeleteAsset deletes an asset and its associated file
 func DeleteAsset(id int) error {
 	// First, get the asset to obtain the file path
@@ -203,6 +222,10 @@ func DeleteAsset(id int) error {
 		return errors.New("asset not found")
 	}
 
+	if _, err := DB.Exec("DELETE FROM asset_embeddings WHERE asset_id = ?", id); err != nil {
+		return err
+	}
+
 	// Delete the database record
 	_, err = DB.Exec("DELETE FROM assets WHERE id = ?", id)
 	if err != nil {
@@ -214,6 +237,306 @@ func DeleteAsset(id int) error {
 	return nil
 }
 
+// SaveAssetEmbedding stores or replaces the vector of an asset for the embedding's model
+func SaveAssetEmbedding(embedding AssetEmbedding) error {
+	_, err := DB.NamedExec(`
+		INSERT INTO asset_embeddings (asset_id, provider_id, model, content_hash, vector, created_at)
+		VALUES (:asset_id, :provider_id, :model, :content_hash, :vector, CURRENT_TIMESTAMP)
+		ON CONFLICT(asset_id, model) DO UPDATE SET
+			provider_id = excluded.provider_id, content_hash = excluded.content_hash,
+			vector = excluded.vector, created_at = CURRENT_TIMESTAMP
+	`, embedding)
+	return err
+}
+
+// ListAssetEmbeddings lists the vectors of all assets embedded with the given model
+func ListAssetEmbeddings(model string) ([]AssetEmbedding, error) {
+	var embeddings []AssetEmbedding
+	err := DB.Select(&embeddings, "SELECT * FROM asset_embeddings WHERE model = ?", model)
+	if err != nil {
+		return nil, err
+	}
+	return embeddings, nil
+}
+
+// CreateGenerationJob records a new generation job
+func CreateGenerationJob(job GenerationJob) (*GenerationJob, error) {
+	result, err := DB.NamedExec(`
+        INSERT INTO generation_jobs (job_id, project_id, provider_id, provider_job_id, kind, model, status, request, result_asset_id, error, created_at, updated_at)
+        VALUES (:job_id, :project_id, :provider_id, :provider_job_id, :kind, :model, :status, :request, :result_asset_id, :error, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+    `, job)
+	if err != nil {
+		return nil, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return GetGenerationJob(int(id))
+}
+
+// GetGenerationJob retrieves a generation job by ID
+func GetGenerationJob(id int) (*GenerationJob, error) {
+	var job GenerationJob
+	err := DB.Get(&job, "SELECT * FROM generation_jobs WHERE id = ?", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// UpdateGenerationJob updates the provider job ID, status and result of a generation job
+func UpdateGenerationJob(job GenerationJob) error {
+	_, err := DB.NamedExec(`
+		UPDATE generation_jobs
+		SET provider_job_id = :provider_job_id, status = :status, result_asset_id = :result_asset_id, error = :error, usage_recorded = :usage_recorded, updated_at = CURRENT_TIMESTAMP
+		WHERE id = :id
+	`, job)
+	return err
+}
+
+// ListUnfinishedGenerationJobs lists jobs that were pending or running when the app last stopped
+func ListUnfinishedGenerationJobs() ([]GenerationJob, error) {
+	var jobs []GenerationJob
+	err := DB.Select(&jobs, "SELECT * FROM generation_jobs WHERE status IN (?, ?) ORDER BY created_at", GenerationJobPending, GenerationJobRunning)
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ListGenerationJobs lists generation jobs for a project. If projectID is 0, lists all jobs.
+func ListGenerationJobs(projectID int) ([]GenerationJob, error) {
+	var jobs []GenerationJob
+	var err error
+	if projectID == 0 {
+		err = DB.Select(&jobs, "SELECT * FROM generation_jobs ORDER BY created_at DESC")
+	} else {
+		err = DB.Select(&jobs, "SELECT * FROM generation_jobs WHERE project_id = ? ORDER BY created_at DESC", projectID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// CreateUsageRecord appends an entry to the usage ledger
+func CreateUsageRecord(record UsageRecord) error {
+	_, err := DB.NamedExec(`
+        INSERT INTO usage_records (project_id, provider_id, provider_type, model, kind, prompt_tokens, output_tokens, total_tokens, count, cost, priced, created_at)
+        VALUES (:project_id, :provider_id, :provider_type, :model, :kind, :prompt_tokens, :output_tokens, :total_tokens, :count, :cost, :priced, CURRENT_TIMESTAMP)
+    `, record)
+	return err
+}
+
+const usageSummaryColumns = `
+	COUNT(*) AS calls,
+	COALESCE(SUM(u.prompt_tokens), 0) AS prompt_tokens,
+	COALESCE(SUM(u.output_tokens), 0) AS output_tokens,
+	COALESCE(SUM(u.total_tokens), 0) AS total_tokens,
+	COALESCE(SUM(u.count), 0) AS count,
+	COALESCE(SUM(u.cost), 0) AS cost,
+	COALESCE(SUM(CASE WHEN u.priced THEN 0 ELSE 1 END), 0) AS unpriced`
+
+// UsageByProject aggregates usage per project, calls made outside a project are grouped under key "0"
+func UsageByProject() ([]UsageSummary, error) {
+	var summaries []UsageSummary
+	err := DB.Select(&summaries, `
+		SELECT CAST(u.project_id AS TEXT) AS key, COALESCE(p.name, '') AS label,`+usageSummaryColumns+`
+		FROM usage_records u
+		LEFT JOIN projects p ON p.id = u.project_id
+		GROUP BY u.project_id
+		ORDER BY cost DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// UsageByProvider aggregates usage per model provider. If projectID is 0, covers all projects.
+func UsageByProvider(projectID int) ([]UsageSummary, error) {
+	var summaries []UsageSummary
+	err := DB.Select(&summaries, `
+		SELECT CAST(u.provider_id AS TEXT) AS key, COALESCE(mp.name, u.provider_type) AS label,`+usageSummaryColumns+`
+		FROM usage_records u
+		LEFT JOIN model_providers mp ON mp.id = u.provider_id
+		WHERE ? = 0 OR u.project_id = ?
+		GROUP BY u.provider_id
+		ORDER BY cost DESC
+	`, projectID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// UsageByDay aggregates usage per calendar day (UTC) over the last days days.
+// If projectID is 0, covers all projects.
+func UsageByDay(projectID int, days int) ([]UsageSummary, error) {
+	if days <= 0 {
+		days = 30
+	}
+	var summaries []UsageSummary
+	err := DB.Select(&summaries, `
+		SELECT DATE(u.created_at) AS key, DATE(u.created_at) AS label,`+usageSummaryColumns+`
+		FROM usage_records u
+		WHERE (? = 0 OR u.project_id = ?) AND u.created_at >= DATE('now', ?)
+		GROUP BY DATE(u.created_at)
+		ORDER BY key
+	`, projectID, projectID, fmt.Sprintf("-%d days", days-1))
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// SaveSpendingBudget saves or updates a spending budget
+func SaveSpendingBudget(budget SpendingBudget) (*SpendingBudget, error) {
+	if budget.ID == 0 {
+		result, err := DB.NamedExec(`
+            INSERT INTO spending_budgets (scope, scope_id, period, limit_usd, action, created_at, updated_at)
+            VALUES (:scope, :scope_id, :period, :limit_usd, :action, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+        `, budget)
+		if err != nil {
+			return nil, err
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		budget.ID = int(id)
+	} else {
+		_, err := DB.NamedExec(`
+			UPDATE spending_budgets
+			SET scope = :scope, scope_id = :scope_id, period = :period, limit_usd = :limit_usd, action = :action, updated_at = CURRENT_TIMESTAMP
+			WHERE id = :id
+		`, budget)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return GetSpendingBudget(budget.ID)
+}
+
+// GetSpendingBudget retrieves a spending budget by ID
+func GetSpendingBudget(id int) (*SpendingBudget, error) {
+	var budget SpendingBudget
+	err := DB.Get(&budget, "SELECT * FROM spending_budgets WHERE id = ?", id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+	return &budget, nil
+}
+
+// DeleteSpendingBudget deletes a spending budget
+func DeleteSpendingBudget(id int) error {
+	_, err := DB.Exec("DELETE FROM spending_budgets WHERE id = ?", id)
+	return err
+}
+
+// ListSpendingBudgets lists all spending budgets
+func ListSpendingBudgets() ([]SpendingBudget, error) {
+	var budgets []SpendingBudget
+	err := DB.Select(&budgets, "SELECT * FROM spending_budgets ORDER BY scope, scope_id, id")
+	if err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+// ListApplicableBudgets lists the global budgets and those of the given project and provider
+func ListApplicableBudgets(projectID, providerID int) ([]SpendingBudget, error) {
+	var budgets []SpendingBudget
+	err := DB.Select(&budgets, `
+		SELECT * FROM spending_budgets
+		WHERE scope = ? OR (scope = ? AND scope_id = ?) OR (scope = ? AND scope_id = ?)
+		ORDER BY id
+	`, BudgetScopeGlobal, BudgetScopeProject, projectID, BudgetScopeProvider, providerID)
+	if err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+// BudgetSpent sums the recorded cost that counts against a budget in its current period
+func BudgetSpent(budget SpendingBudget) (float64, error) {
+	query := "SELECT COALESCE(SUM(cost), 0) FROM usage_records WHERE 1 = 1"
+	var args []interface{}
+
+	switch budget.Scope {
+	case BudgetScopeProject:
+		query += " AND project_id = ?"
+		args = append(args, budget.ScopeID)
+	case BudgetScopeProvider:
+		query += " AND provider_id = ?"
+		args = append(args, budget.ScopeID)
+	}
+	if budget.Period == BudgetPeriodMonthly {
+		query += " AND created_at >= DATE('now', 'start of month')"
+	}
+
+	var spent float64
+	if err := DB.Get(&spent, query, args...); err != nil {
+		return 0, err
+	}
+	return spent, nil
+}
+
+// SaveModelOverride creates or replaces the override of a provider's model
+func SaveModelOverride(override ModelOverride) (*ModelOverride, error) {
+	_, err := DB.NamedExec(`
+		INSERT INTO model_overrides (provider_id, model_id, display_name, input, output, created_at, updated_at)
+		VALUES (:provider_id, :model_id, :display_name, :input, :output, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		ON CONFLICT(provider_id, model_id) DO UPDATE SET
+			display_name = excluded.display_name, input = excluded.input, output = excluded.output,
+			updated_at = CURRENT_TIMESTAMP
+	`, override)
+	if err != nil {
+		return nil, err
+	}
+	return GetModelOverride(override.ProviderID, override.ModelID)
+}
+
+// GetModelOverride retrieves the override of a provider's model
+func GetModelOverride(providerID int, modelID string) (*ModelOverride, error) {
+	var override ModelOverride
+	err := DB.Get(&override, "SELECT * FROM model_overrides WHERE provider_id = ? AND model_id = ?", providerID, modelID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+	return &override, nil
+}
+
+// DeleteModelOverride deletes a model override
+func DeleteModelOverride(id int) error {
+	_, err := DB.Exec("DELETE FROM model_overrides WHERE id = ?", id)
+	return err
+}
+
+// ListModelOverrides lists the model overrides of a provider, or of all providers when providerID is 0
+func ListModelOverrides(providerID int) ([]ModelOverride, error) {
+	var overrides []ModelOverride
+	err := DB.Select(&overrides, `
+		SELECT * FROM model_overrides
+		WHERE ? = 0 OR provider_id = ?
+		ORDER BY provider_id, model_id
+	`, providerID, providerID)
+	if err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
 // GetUserPreference retrieves a user preference by key
 func GetUserPreference(key string) (string, error) {
 	var pref UserPreference
@@ -10,6 +10,7 @@ import { CheckUpdate } from "../wailsjs/go/app/Service";
 import { UpdateDialog } from "@/components/update-dialog";
 import { GetInitError } from "../wailsjs/go/app/Service";
 import { StartupErrorScreen } from "@/components/startup/startup-error-screen";
+import { useVideoJobEvents } from "@/hooks/use-video-job-events";
 
 export default function App() {
   const [selectedProject, setSelectedProject] =
@@ -19,6 +20,7 @@ export default function App() {
   const [updateInfo, setUpdateInfo] = useState<any>(null);
   const [showUpdateDialog, setShowUpdateDialog] = useState(false);
   const [initError, setInitError] = useState<string | null>(null);
+  useVideoJobEvents();
 
   useEffect(() => {
     GetInitError().then((err) => {
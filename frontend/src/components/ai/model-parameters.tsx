@@ -0,0 +1,142 @@
+import { useEffect, useState } from "react";
+import { Input } from "@/components/ui/input";
+import {
+    Select,
+    SelectContent,
+    SelectItem,
+    SelectTrigger,
+    SelectValue,
+} from "@/components/ui/select";
+import { Trans } from "@lingui/react/macro";
+import { GetModelParameters } from "../../../wailsjs/go/ai/Service";
+import { ai } from "../../../wailsjs/go/models";
+
+// Select value standing for "option not set"
+const DEFAULT_VALUE = "__default__";
+
+interface ModelParametersProps {
+    providerId?: number;
+    modelId?: string;
+    options?: Record<string, any>;
+    onOptionsChange: (options: Record<string, any> | undefined) => void;
+}
+
+export function ModelParameters({
+    providerId,
+    modelId,
+    options,
+    onOptionsChange,
+}: ModelParametersProps) {
+    const [parameters, setParameters] = useState<ai.Parameter[]>([]);
+
+    // Load the parameters of the selected model
+    useEffect(() => {
+        if (!providerId || !modelId) {
+            setParameters([]);
+            return;
+        }
+        let cancelled = false;
+        GetModelParameters(providerId, modelId)
+            .then((list) => {
+                if (!cancelled) setParameters(list || []);
+            })
+            .catch((e) => {
+                console.error("Failed to load model parameters", e);
+                if (!cancelled) setParameters([]);
+            });
+        return () => {
+            cancelled = true;
+        };
+    }, [providerId, modelId]);
+
+    if (parameters.length === 0) {
+        return null;
+    }
+
+    const setOption = (name: string, value: any) => {
+        const next = { ...(options || {}) };
+        if (value === undefined) {
+            delete next[name];
+        } else {
+            next[name] = value;
+        }
+        onOptionsChange(Object.keys(next).length > 0 ? next : undefined);
+    };
+
+    const renderControl = (parameter: ai.Parameter) => {
+        const value = options?.[parameter.name];
+        const placeholder =
+            parameter.default !== undefined ? String(parameter.default) : undefined;
+
+        if (parameter.enum?.length || parameter.type === "boolean") {
+            const choices: any[] = parameter.enum?.length ? parameter.enum : [true, false];
+            return (
+                <Select
+                    value={value === undefined ? DEFAULT_VALUE : String(value)}
+                    onValueChange={(selected) =>
+                        setOption(
+                            parameter.name,
+                            selected === DEFAULT_VALUE
+                                ? undefined
+                                : choices.find((choice) => String(choice) === selected)
+                        )
+                    }
+                >
+                    <SelectTrigger size="sm" className="w-full">
+                        <SelectValue />
+                    </SelectTrigger>
+                    <SelectContent>
+                        <SelectItem value={DEFAULT_VALUE}>
+                            <Trans>Default</Trans>
+                            {placeholder !== undefined && ` (${placeholder})`}
+                        </SelectItem>
+                        {choices.map((choice) => (
+                            <SelectItem key={String(choice)} value={String(choice)}>
+                                {String(choice)}
+                            </SelectItem>
+                        ))}
+                    </SelectContent>
+                </Select>
+            );
+        }
+
+        const numeric = parameter.type === "integer" || parameter.type === "number";
+        return (
+            <Input
+                className="h-8"
+                type={numeric ? "number" : "text"}
+                min={parameter.min}
+                max={parameter.max}
+                step={parameter.type === "integer" ? 1 : "any"}
+                placeholder={placeholder}
+                value={value ?? ""}
+                onChange={(e) => {
+                    const raw = e.target.value;
+                    if (raw === "") {
+                        setOption(parameter.name, undefined);
+                    } else if (numeric) {
+                        const number = Number(raw);
+                        if (!Number.isNaN(number)) setOption(parameter.name, number);
+                    } else {
+                        setOption(parameter.name, raw);
+                    }
+                }}
+            />
+        );
+    };
+
+    return (
+        <div className="grid grid-cols-3 gap-2 p-2 border-t">
+            {parameters.map((parameter) => (
+                <label
+                    key={parameter.name}
+                    className="flex flex-col gap-1 text-xs text-muted-foreground"
+                    title={parameter.description}
+                >
+                    {parameter.label}
+                    {renderControl(parameter)}
+                </label>
+            ))}
+        </div>
+    );
+}
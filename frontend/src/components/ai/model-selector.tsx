@@ -19,6 +19,7 @@ import { ListModels } from "../../../wailsjs/go/ai/Service";
 import { database, ai } from "../../../wailsjs/go/models";
 import { Check, ChevronsUpDown, Loader2 } from "lucide-react";
 import { cn } from "@/lib/utils";
+import { formatPrice, formatTokens } from "@/lib/models";
 import { useLingui } from "@lingui/react";
 import { msg } from "@lingui/core/macro";
 
@@ -166,7 +167,7 @@ export function ModelSelector({
                                             {models.map((model) => (
                                                 <CommandItem
                                                     key={model.id}
-                                                    value={model.id}
+                                                    value={model.display_name ? `${model.id} ${model.display_name}` : model.id}
                                                     onSelect={() => handleModelSelect(model.id)}
                                                     className="text-xs"
                                                 >
@@ -180,7 +181,9 @@ export function ModelSelector({
                                                                         : "opacity-0"
                                                                 )}
                                                             />
-                                                            <span className="truncate">{model.id}</span>
+                                                            <span className="truncate" title={model.id}>
+                                                                {model.display_name || model.id}
+                                                            </span>
                                                         </div>
                                                         <div className="flex flex-wrap gap-1 pl-6">
                                                             {model.input?.map((i) => (
@@ -199,6 +202,19 @@ export function ModelSelector({
                                                                     Out: {o}
                                                                 </span>
                                                             ))}
+                                                            {!!model.context_window && (
+                                                                <span className="px-1 rounded bg-muted text-muted-foreground text-[10px]">
+                                                                    {formatTokens(model.context_window)} ctx
+                                                                </span>
+                                                            )}
+                                                            {model.cost && (model.cost.input > 0 || model.cost.output > 0) && (
+                                                                <span
+                                                                    className="px-1 rounded bg-muted text-muted-foreground text-[10px]"
+                                                                    title={_(msg`USD per million input / output tokens`)}
+                                                                >
+                                                                    {formatPrice(model.cost.input)} / {formatPrice(model.cost.output)}
+                                                                </span>
+                                                            )}
                                                         </div>
                                                     </div>
                                                 </CommandItem>
@@ -5,6 +5,7 @@ import type { WorkflowNodeData } from "./types";
 import { Loader2, Play } from "lucide-react";
 import { useReactFlow } from "@xyflow/react";
 import { ModelSelector } from "@/components/ai/model-selector";
+import { ModelParameters } from "@/components/ai/model-parameters";
 
 interface NodeParametersPanelProps {
   nodeId: string;
@@ -23,11 +24,17 @@ export function NodeParametersPanel({
 
   const handleProviderChange = (providerId: number) => {
     // Select provider and clear model
-    updateNodeData(nodeId, { providerId, modelId: "" });
+    updateNodeData(nodeId, { providerId, modelId: "", options: undefined });
   };
 
   const handleModelChange = (modelId: string) => {
-    updateNodeData(nodeId, { modelId });
+    if (modelId === nodeData.modelId) return;
+    // Options are model specific, so they do not carry over
+    updateNodeData(nodeId, { modelId, options: undefined });
+  };
+
+  const handleOptionsChange = (options: Record<string, any> | undefined) => {
+    updateNodeData(nodeId, { options });
   };
 
   const handlePromptChange = (e: React.ChangeEvent<HTMLTextAreaElement>) => {
@@ -51,6 +58,13 @@ export function NodeParametersPanel({
         )}
       </div>
 
+      <ModelParameters
+        providerId={nodeData.providerId}
+        modelId={nodeData.modelId}
+        options={nodeData.options}
+        onOptionsChange={handleOptionsChange}
+      />
+
       <div className="p-2 border-t bg-muted/30 flex gap-2 justify-between">
         <ModelSelector
           providerId={nodeData.providerId}
@@ -6,6 +6,7 @@ export interface BaseNodeData {
   type: NodeType;
   modelId?: string;
   providerId?: number;
+  options?: Record<string, any>;
   processing?: boolean;
   error?: string;
   prompt?: string;
@@ -0,0 +1,143 @@
+import { useEffect, useState } from "react";
+import { Button } from "@/components/ui/button";
+import { Input } from "@/components/ui/input";
+import { Label } from "@/components/ui/label";
+import {
+    Dialog,
+    DialogContent,
+    DialogDescription,
+    DialogFooter,
+    DialogHeader,
+    DialogTitle,
+} from "@/components/ui/dialog";
+import { Loader2 } from "lucide-react";
+import { toast } from "sonner";
+import { Trans } from "@lingui/react/macro";
+import { useLingui } from "@lingui/react";
+import { msg } from "@lingui/core/macro";
+import { SaveModelOverride, DeleteModelOverride } from "../../../wailsjs/go/database/Service";
+import { ai, database } from "../../../wailsjs/go/models";
+
+const MODALITIES = ["text", "image", "audio", "video", "pdf", "embedding"];
+
+interface ModelOverrideDialogProps {
+    model: ai.Model | null;
+    override?: database.ModelOverride;
+    onOpenChange: (open: boolean) => void;
+    onSaved: () => void;
+}
+
+export function ModelOverrideDialog({ model, override, onOpenChange, onSaved }: ModelOverrideDialogProps) {
+    const { _ } = useLingui();
+    const [displayName, setDisplayName] = useState("");
+    const [input, setInput] = useState<string[]>([]);
+    const [output, setOutput] = useState<string[]>([]);
+    const [saving, setSaving] = useState(false);
+
+    // Start from the saved override, or from what the model currently reports
+    useEffect(() => {
+        if (!model) return;
+        setDisplayName(override?.displayName || "");
+        setInput(override?.input?.length ? override.input : model.input || []);
+        setOutput(override?.output?.length ? override.output : model.output || []);
+    }, [model, override]);
+
+    const toggle = (list: string[], setList: (list: string[]) => void, modality: string) => {
+        setList(list.includes(modality) ? list.filter((m) => m !== modality) : [...list, modality]);
+    };
+
+    const handleSave = async () => {
+        if (!model?.provider_id) return;
+        setSaving(true);
+        try {
+            await SaveModelOverride(
+                database.ModelOverride.createFrom({
+                    providerId: model.provider_id,
+                    modelId: model.id,
+                    displayName,
+                    input,
+                    output,
+                })
+            );
+            toast.success(_(msg`Model override saved`));
+            onSaved();
+            onOpenChange(false);
+        } catch (err: any) {
+            toast.error(_(msg`Failed to save model override`) + ": " + err);
+        } finally {
+            setSaving(false);
+        }
+    };
+
+    const handleReset = async () => {
+        if (!override) return;
+        setSaving(true);
+        try {
+            await DeleteModelOverride(override.id);
+            onSaved();
+            onOpenChange(false);
+        } catch (err: any) {
+            toast.error(_(msg`Failed to delete model override`) + ": " + err);
+        } finally {
+            setSaving(false);
+        }
+    };
+
+    const renderModalities = (list: string[], setList: (list: string[]) => void) => (
+        <div className="flex flex-wrap gap-1">
+            {MODALITIES.map((modality) => (
+                <Button
+                    key={modality}
+                    type="button"
+                    size="sm"
+                    variant={list.includes(modality) ? "default" : "outline"}
+                    onClick={() => toggle(list, setList, modality)}
+                >
+                    {modality}
+                </Button>
+            ))}
+        </div>
+    );
+
+    return (
+        <Dialog open={model !== null} onOpenChange={onOpenChange}>
+            <DialogContent>
+                <DialogHeader>
+                    <DialogTitle><Trans>Edit Model</Trans></DialogTitle>
+                    <DialogDescription className="font-mono text-xs break-all">{model?.id}</DialogDescription>
+                </DialogHeader>
+
+                <div className="space-y-4">
+                    <div className="space-y-2">
+                        <Label><Trans>Display Name</Trans></Label>
+                        <Input
+                            value={displayName}
+                            placeholder={model?.id}
+                            onChange={(e) => setDisplayName(e.target.value)}
+                        />
+                    </div>
+                    <div className="space-y-2">
+                        <Label><Trans>Input</Trans></Label>
+                        {renderModalities(input, setInput)}
+                    </div>
+                    <div className="space-y-2">
+                        <Label><Trans>Output</Trans></Label>
+                        {renderModalities(output, setOutput)}
+                    </div>
+                </div>
+
+                <DialogFooter>
+                    {override && (
+                        <Button variant="outline" onClick={handleReset} disabled={saving}>
+                            <Trans>Reset</Trans>
+                        </Button>
+                    )}
+                    <Button onClick={handleSave} disabled={saving}>
+                        {saving && <Loader2 className="mr-2 h-4 w-4 animate-spin" />}
+                        <Trans>Save</Trans>
+                    </Button>
+                </DialogFooter>
+            </DialogContent>
+        </Dialog>
+    );
+}
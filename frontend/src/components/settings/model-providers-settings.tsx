@@ -41,6 +41,8 @@ const PROVIDER_TYPES = [
   { value: "gemini", label: "Google Gemini" },
   { value: "openai", label: "OpenAI" },
   { value: "claude", label: "Anthropic Claude" },
+  { value: "ollama", label: "Ollama" },
+  { value: "mock", label: "Mock (offline)" },
 ];
 
 export function ModelProvidersSettings() {
@@ -314,6 +316,24 @@ export function ModelProvidersSettings() {
                 placeholder="https://api.example.com/v1"
               />
             </div>
+            <div className="grid gap-2">
+              <Label htmlFor="embeddingModel">
+                Embedding Model (<Trans>Optional</Trans>)
+              </Label>
+              <Input
+                id="embeddingModel"
+                value={formData.embeddingModel || ""}
+                onChange={(e) =>
+                  setFormData(
+                    new database.ModelProvider({
+                      ...formData,
+                      embeddingModel: e.target.value,
+                    })
+                  )
+                }
+                placeholder="text-embedding-3-small"
+              />
+            </div>
           </div>
           <DialogFooter>
             <Button variant="outline" onClick={handleCloseDialog}>
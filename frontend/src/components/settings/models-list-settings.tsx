@@ -1,27 +1,53 @@
 import { useEffect, useState } from "react";
-import { Loader2, RefreshCw, Box } from "lucide-react";
+import { Loader2, RefreshCw, Box, Pencil } from "lucide-react";
 import { Button } from "@/components/ui/button";
 import { Card, CardHeader, CardTitle, CardContent } from "@/components/ui/card";
-import { ListModels } from "../../../wailsjs/go/ai/Service";
-import { ai } from "../../../wailsjs/go/models";
+import { ListModels, RefreshCapabilities, CapabilitiesStatus } from "../../../wailsjs/go/ai/Service";
+import { ListModelOverrides } from "../../../wailsjs/go/database/Service";
+import { ai, database } from "../../../wailsjs/go/models";
+import { ModelOverrideDialog } from "./model-override-dialog";
 import { Trans } from "@lingui/react/macro";
 import { useLingui } from "@lingui/react";
 import { msg } from "@lingui/core/macro";
+import { toast } from "sonner";
 
 export function ModelsListSettings() {
     const { _ } = useLingui();
     const [models, setModels] = useState<ai.Model[]>([]);
     const [loading, setLoading] = useState(false);
+    const [capabilities, setCapabilities] = useState<ai.CapabilitiesStatus | null>(null);
+    const [refreshingData, setRefreshingData] = useState(false);
+    const [overrides, setOverrides] = useState<database.ModelOverride[]>([]);
+    const [editing, setEditing] = useState<ai.Model | null>(null);
 
     useEffect(() => {
         loadModels();
+        CapabilitiesStatus().then(setCapabilities).catch((err) => {
+            console.error("Failed to load model data status", err);
+        });
     }, []);
 
+    // Fetch the latest capabilities from models.dev, then reload so the list reflects them
+    const refreshModelData = async () => {
+        setRefreshingData(true);
+        try {
+            setCapabilities(await RefreshCapabilities());
+            toast.success(_(msg`Model data updated`));
+            await loadModels();
+        } catch (err: any) {
+            toast.error(_(msg`Failed to update model data`) + ": " + err);
+            CapabilitiesStatus().then(setCapabilities).catch(() => {});
+        } finally {
+            setRefreshingData(false);
+        }
+    };
+
     const loadModels = async () => {
         setLoading(true);
         try {
-            const list = await ListModels(null);
+            const [list, overrideList] = await Promise.all([ListModels(null), ListModelOverrides(0)]);
             setModels(list || []);
+            setOverrides(overrideList || []);
         } catch (err) {
             console.error("Failed to load models", err);
         } finally {
@@ -29,17 +55,34 @@ export function ModelsListSettings() {
         }
     };
 
+    const findOverride = (model: ai.Model) =>
+        overrides.find(
+            (o) => o.providerId === model.provider_id && o.modelId.toLowerCase() === model.id.toLowerCase()
+        );
+
     return (
         <div className="p-8 max-w-4xl h-full flex flex-col">
             <div className="mb-6 flex items-center justify-between">
                 <div>
                     <h3 className="text-lg font-medium"><Trans>Available Models</Trans></h3>
                     <p className="text-sm text-muted-foreground"><Trans>View AI models from all configured providers.</Trans></p>
+                    {capabilities && (
+                        <p className="text-xs text-muted-foreground mt-1" title={capabilities.lastError}>
+                            <Trans>Model data</Trans>: {capabilities.source}
+                            {capabilities.fetchedAt && ` · ${new Date(capabilities.fetchedAt).toLocaleString()}`}
+                        </p>
+                    )}
+                </div>
+                <div className="flex gap-2">
+                    <Button variant="outline" size="sm" onClick={refreshModelData} disabled={refreshingData}>
+                        <RefreshCw className={`mr-2 h-4 w-4 ${refreshingData ? "animate-spin" : ""}`} />
+                        <Trans>Update Model Data</Trans>
+                    </Button>
+                    <Button variant="outline" size="sm" onClick={loadModels} disabled={loading}>
+                        <RefreshCw className={`mr-2 h-4 w-4 ${loading ? "animate-spin" : ""}`} />
+                        <Trans>Refresh</Trans>
+                    </Button>
                 </div>
-                <Button variant="outline" size="sm" onClick={loadModels} disabled={loading}>
-                    <RefreshCw className={`mr-2 h-4 w-4 ${loading ? "animate-spin" : ""}`} />
-                    <Trans>Refresh</Trans>
-                </Button>
             </div>
 
             <div className="flex-1 overflow-auto">
@@ -58,8 +101,21 @@ export function ModelsListSettings() {
                     <div className="flex flex-col gap-2">
                         {models.map((model, index) => (
                             <div key={`${model.id}-${index}`} className="group px-3 py-2 border rounded-md hover:bg-accent/50 transition-colors bg-card flex flex-col justify-center min-w-35">
-                                <div className="font-mono text-xs font-medium truncate" title={model.id}>
-                                    {model.id}
+                                <div className="flex items-center justify-between gap-2">
+                                    <div className="font-mono text-xs font-medium truncate" title={model.id}>
+                                        {model.display_name || model.id}
+                                    </div>
+                                    {model.provider_id ? (
+                                        <Button
+                                            variant="ghost"
+                                            size="icon"
+                                            className="h-6 w-6 shrink-0 opacity-0 group-hover:opacity-100"
+                                            title={_(msg`Edit model`)}
+                                            onClick={() => setEditing(model)}
+                                        >
+                                            <Pencil className="h-3 w-3" />
+                                        </Button>
+                                    ) : null}
                                 </div>
                                 <div className="flex justify-between items-center mt-1 gap-2">
                                     <span className="text-[10px] text-muted-foreground truncate flex items-center gap-1">
@@ -88,6 +144,13 @@ export function ModelsListSettings() {
                     </div>
                 )}
             </div>
+
+            <ModelOverrideDialog
+                model={editing}
+                override={editing ? findOverride(editing) : undefined}
+                onOpenChange={(open) => !open && setEditing(null)}
+                onSaved={loadModels}
+            />
         </div >
     );
 }
@@ -1,6 +1,11 @@
 import { useReactFlow } from "@xyflow/react";
 import { type BaseNodeData } from "../components/nodes/types";
 import { useEffect, useRef } from "react";
+import { toast } from "sonner";
+import { msg } from "@lingui/core/macro";
+import { useLingui } from "@lingui/react";
+import { GetModelInfo } from "../../wailsjs/go/ai/Service";
+import { estimateTokens, formatTokens } from "@/lib/models";
 
 interface UseNodeRunProps {
     id: string;
@@ -14,12 +19,14 @@ interface UseNodeRunProps {
         audios?: string[];
         documents?: string[];
         projectId?: number;
+        options?: Record<string, any>;
     }) => Promise<any>;
     onSuccess: (response: any) => void;
     onStart?: () => void;
 }
 
 export function useNodeRun({ id, nodeData, apiFunction, onSuccess, onStart }: UseNodeRunProps) {
+    const { _ } = useLingui();
     const { updateNodeData, getEdges, setEdges, getNode } = useReactFlow();
 
     // Keep track of the last handled trigger to avoid loops/double runs
@@ -79,6 +86,22 @@ export function useNodeRun({ id, nodeData, apiFunction, onSuccess, onStart }: Us
 
         const mergedPrompt = `${sourcePrompts.join("\n\n")}\n\n${nodeData.prompt ?? ""}`.trim();
 
+        // Warn, but still run, when the prompt likely does not fit the model's context window
+        try {
+            const info = await GetModelInfo(nodeData.providerId, nodeData.modelId);
+            const tokens = estimateTokens(mergedPrompt);
+            if (info.context_window && tokens > info.context_window) {
+                const promptTokens = formatTokens(tokens);
+                const contextWindow = formatTokens(info.context_window);
+                const model = nodeData.modelId;
+                toast.warning(
+                    _(msg`The prompt is about ${promptTokens} tokens, more than the ${contextWindow} token context window of ${model}`)
+                );
+            }
+        } catch (err) {
+            console.error("Failed to check model limits", err);
+        }
+
         console.log("--- Executing Node", id, "---");
         console.log("Inputs:", { images, videos, audios, documents, mergedPrompt });
 
@@ -92,6 +115,7 @@ export function useNodeRun({ id, nodeData, apiFunction, onSuccess, onStart }: Us
                 audios: audios.length > 0 ? audios : undefined,
                 documents: documents.length > 0 ? documents : undefined,
                 projectId: nodeData.projectId,
+                options: nodeData.options,
             });
             console.log("Node execution response:", response);
             updateNodeData(id, { processing: false });
@@ -0,0 +1,39 @@
+import { useEffect } from "react";
+import { toast } from "sonner";
+import { msg } from "@lingui/core/macro";
+import { useLingui } from "@lingui/react";
+import { EventsOn } from "../../wailsjs/runtime/runtime";
+import { ResumeGenerationJobs } from "../../wailsjs/go/ai/Service";
+
+// Payload of the video job events, see VideoJobEvent in binding/ai/video_jobs.go
+interface VideoJobEvent {
+    jobId: string;
+    projectId?: number;
+    error?: string;
+}
+
+/**
+ * Offers to resume video jobs that stopped before their result was saved, e.g. after a network failure.
+ * The provider keeps the job, so resuming picks up the finished video without paying again.
+ */
+export function useVideoJobEvents() {
+    const { _ } = useLingui();
+
+    useEffect(() => {
+        return EventsOn("ai:job:interrupted", (event: VideoJobEvent) => {
+            toast.error(_(msg`Video generation was interrupted`), {
+                id: event.jobId,
+                description: event.error,
+                duration: Infinity,
+                action: {
+                    label: _(msg`Resume`),
+                    onClick: () => {
+                        ResumeGenerationJobs().catch((err) => {
+                            toast.error(_(msg`Failed to resume video generation`) + ": " + err);
+                        });
+                    },
+                },
+            });
+        });
+    }, [_]);
+}
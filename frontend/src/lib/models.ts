@@ -0,0 +1,16 @@
+// Formats a token count compactly, e.g. 128000 -> "128K"
+export function formatTokens(tokens: number): string {
+  if (tokens >= 1_000_000) return `${+(tokens / 1_000_000).toFixed(1)}M`;
+  if (tokens >= 1_000) return `${Math.round(tokens / 1_000)}K`;
+  return String(tokens);
+}
+
+// Formats a price in USD per million tokens
+export function formatPrice(usd: number): string {
+  return `$${+usd.toFixed(usd < 1 ? 3 : 2)}`;
+}
+
+// Roughly estimates the tokens of a text, about four characters per token
+export function estimateTokens(text: string): number {
+  return Math.ceil(text.length / 4);
+}
@@ -2,12 +2,34 @@
 // This file is automatically generated. DO NOT EDIT
 import {ai} from '../models';
 
+export function CancelJob(arg1:string):Promise<void>;
+
+export function CapabilitiesStatus():Promise<ai.CapabilitiesStatus>;
+
 export function GenerateAudio(arg1:ai.AudioRequest):Promise<ai.AIResponse>;
 
 export function GenerateImage(arg1:ai.ImageRequest):Promise<ai.AIResponse>;
 
+export function GenerateSubtitles(arg1:ai.SubtitleRequest):Promise<ai.SubtitleResult>;
+
 export function GenerateText(arg1:ai.TextRequest):Promise<ai.AIResponse>;
 
 export function GenerateVideo(arg1:ai.VideoRequest):Promise<ai.AIResponse>;
 
+export function GetModelInfo(arg1:number,arg2:string):Promise<ai.Model>;
+
+export function GetModelParameters(arg1:number,arg2:string):Promise<Array<ai.Parameter>>;
+
+export function ListActiveJobs():Promise<Array<ai.JobInfo>>;
+
 export function ListModels(arg1:any):Promise<Array<ai.Model>>;
+
+export function PlanWorkflow(arg1:string,arg2:number,arg3:number,arg4:string):Promise<ai.WorkflowPlan>;
+
+export function RefreshCapabilities():Promise<ai.CapabilitiesStatus>;
+
+export function ResumeGenerationJobs():Promise<void>;
+
+export function StreamText(arg1:string,arg2:ai.TextRequest):Promise<ai.AIResponse>;
+
+export function TranscribeAudio(arg1:ai.TranscribeRequest):Promise<ai.AIResponse>;
@@ -2,6 +2,14 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function CancelJob(arg1) {
+  return window['go']['ai']['Service']['CancelJob'](arg1);
+}
+
+export function CapabilitiesStatus() {
+  return window['go']['ai']['Service']['CapabilitiesStatus']();
+}
+
 export function GenerateAudio(arg1) {
   return window['go']['ai']['Service']['GenerateAudio'](arg1);
 }
@@ -10,6 +18,10 @@ export function GenerateImage(arg1) {
   return window['go']['ai']['Service']['GenerateImage'](arg1);
 }
 
+export function GenerateSubtitles(arg1) {
+  return window['go']['ai']['Service']['GenerateSubtitles'](arg1);
+}
+
 export function GenerateText(arg1) {
   return window['go']['ai']['Service']['GenerateText'](arg1);
 }
@@ -18,6 +30,38 @@ export function GenerateVideo(arg1) {
   return window['go']['ai']['Service']['GenerateVideo'](arg1);
 }
 
+export function GetModelInfo(arg1, arg2) {
+  return window['go']['ai']['Service']['GetModelInfo'](arg1, arg2);
+}
+
+export function GetModelParameters(arg1, arg2) {
+  return window['go']['ai']['Service']['GetModelParameters'](arg1, arg2);
+}
+
+export function ListActiveJobs() {
+  return window['go']['ai']['Service']['ListActiveJobs']();
+}
+
 export function ListModels(arg1) {
   return window['go']['ai']['Service']['ListModels'](arg1);
 }
+
+export function PlanWorkflow(arg1, arg2, arg3, arg4) {
+  return window['go']['ai']['Service']['PlanWorkflow'](arg1, arg2, arg3, arg4);
+}
+
+export function RefreshCapabilities() {
+  return window['go']['ai']['Service']['RefreshCapabilities']();
+}
+
+export function ResumeGenerationJobs() {
+  return window['go']['ai']['Service']['ResumeGenerationJobs']();
+}
+
+export function StreamText(arg1, arg2) {
+  return window['go']['ai']['Service']['StreamText'](arg1, arg2);
+}
+
+export function TranscribeAudio(arg1) {
+  return window['go']['ai']['Service']['TranscribeAudio'](arg1);
+}
@@ -6,22 +6,46 @@ export function CreateAssetFromFile(arg1:string,arg2:Array<number>):Promise<data
 
 export function DeleteAsset(arg1:number):Promise<void>;
 
+export function DeleteModelOverride(arg1:number):Promise<void>;
+
 export function DeleteModelProvider(arg1:number):Promise<void>;
 
 export function DeleteProject(arg1:number):Promise<void>;
 
+export function DeleteSpendingBudget(arg1:number):Promise<void>;
+
 export function DownloadAssetFile(arg1:string):Promise<void>;
 
 export function GetModelProvider(arg1:number):Promise<database.ModelProvider>;
 
 export function GetProject(arg1:number):Promise<database.Project>;
 
+export function GetUsageByDay(arg1:number,arg2:number):Promise<Array<database.UsageSummary>>;
+
+export function GetUsageByProject():Promise<Array<database.UsageSummary>>;
+
+export function GetUsageByProvider(arg1:number):Promise<Array<database.UsageSummary>>;
+
 export function ListAssets(arg1:number):Promise<Array<database.Asset>>;
 
+export function ListGenerationAssets(arg1:string):Promise<Array<database.Asset>>;
+
+export function ListGenerationJobs(arg1:number):Promise<Array<database.GenerationJob>>;
+
+export function ListModelOverrides(arg1:number):Promise<Array<database.ModelOverride>>;
+
 export function ListModelProviders():Promise<Array<database.ModelProvider>>;
 
 export function ListProjects():Promise<Array<database.Project>>;
 
+export function ListSpendingBudgets():Promise<Array<database.BudgetStatus>>;
+
+export function SaveModelOverride(arg1:database.ModelOverride):Promise<database.ModelOverride>;
+
 export function SaveModelProvider(arg1:database.ModelProvider):Promise<void>;
 
 export function SaveProject(arg1:database.Project):Promise<database.Project>;
+
+export function SaveSpendingBudget(arg1:database.SpendingBudget):Promise<database.SpendingBudget>;
+
+export function SearchAssets(arg1:string):Promise<Array<database.AssetMatch>>;
@@ -10,6 +10,10 @@ export function DeleteAsset(arg1) {
   return window['go']['database']['Service']['DeleteAsset'](arg1);
 }
 
+export function DeleteModelOverride(arg1) {
+  return window['go']['database']['Service']['DeleteModelOverride'](arg1);
+}
+
 export function DeleteModelProvider(arg1) {
   return window['go']['database']['Service']['DeleteModelProvider'](arg1);
 }
@@ -18,6 +22,10 @@ export function DeleteProject(arg1) {
   return window['go']['database']['Service']['DeleteProject'](arg1);
 }
 
+export function DeleteSpendingBudget(arg1) {
+  return window['go']['database']['Service']['DeleteSpendingBudget'](arg1);
+}
+
 export function DownloadAssetFile(arg1) {
   return window['go']['database']['Service']['DownloadAssetFile'](arg1);
 }
@@ -30,10 +38,34 @@ export function GetProject(arg1) {
   return window['go']['database']['Service']['GetProject'](arg1);
 }
 
+export function GetUsageByDay(arg1, arg2) {
+  return window['go']['database']['Service']['GetUsageByDay'](arg1, arg2);
+}
+
+export function GetUsageByProject() {
+  return window['go']['database']['Service']['GetUsageByProject']();
+}
+
+export function GetUsageByProvider(arg1) {
+  return window['go']['database']['Service']['GetUsageByProvider'](arg1);
+}
+
 export function ListAssets(arg1) {
   return window['go']['database']['Service']['ListAssets'](arg1);
 }
 
+export function ListGenerationAssets(arg1) {
+  return window['go']['database']['Service']['ListGenerationAssets'](arg1);
+}
+
+export function ListGenerationJobs(arg1) {
+  return window['go']['database']['Service']['ListGenerationJobs'](arg1);
+}
+
+export function ListModelOverrides(arg1) {
+  return window['go']['database']['Service']['ListModelOverrides'](arg1);
+}
+
 export function ListModelProviders() {
   return window['go']['database']['Service']['ListModelProviders']();
 }
@@ -42,6 +74,14 @@ export function ListProjects() {
   return window['go']['database']['Service']['ListProjects']();
 }
 
+export function ListSpendingBudgets() {
+  return window['go']['database']['Service']['ListSpendingBudgets']();
+}
+
+export function SaveModelOverride(arg1) {
+  return window['go']['database']['Service']['SaveModelOverride'](arg1);
+}
+
 export function SaveModelProvider(arg1) {
   return window['go']['database']['Service']['SaveModelProvider'](arg1);
 }
@@ -49,3 +89,11 @@ export function SaveModelProvider(arg1) {
 export function SaveProject(arg1) {
   return window['go']['database']['Service']['SaveProject'](arg1);
 }
+
+export function SaveSpendingBudget(arg1) {
+  return window['go']['database']['Service']['SaveSpendingBudget'](arg1);
+}
+
+export function SearchAssets(arg1) {
+  return window['go']['database']['Service']['SearchAssets'](arg1);
+}
@@ -1,7 +1,9 @@
 export namespace ai {
 	
 	export class AIResponse {
+	    jobId?: string;
 	    content: string;
+	    contents?: string[];
 	    usage?: Record<string, any>;
 	    raw?: any;
 	
@@ -11,12 +13,15 @@ export namespace ai {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
 	        this.content = source["content"];
+	        this.contents = source["contents"];
 	        this.usage = source["usage"];
 	        this.raw = source["raw"];
 	    }
 	}
 	export class AudioRequest {
+	    jobId?: string;
 	    projectId?: number;
 	    prompt: string;
 	    images?: string[];
@@ -34,6 +39,7 @@ export namespace ai {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
 	        this.projectId = source["projectId"];
 	        this.prompt = source["prompt"];
 	        this.images = source["images"];
@@ -46,17 +52,63 @@ export namespace ai {
 	        this.options = source["options"];
 	    }
 	}
+	export class CapabilitiesStatus {
+	    source: string;
+	    models: number;
+	    // Go type: time
+	    fetchedAt?: any;
+	    lastError?: string;
+	    refreshing: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CapabilitiesStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.source = source["source"];
+	        this.models = source["models"];
+	        this.fetchedAt = this.convertValues(source["fetchedAt"], null);
+	        this.lastError = source["lastError"];
+	        this.refreshing = source["refreshing"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class ImageRequest {
+	    jobId?: string;
 	    projectId?: number;
 	    prompt: string;
 	    images?: string[];
 	    videos?: string[];
 	    audios?: string[];
+	    maskAssetId?: number;
 	    model: string;
 	    providerId: number;
 	    size?: string;
 	    quality?: string;
 	    style?: string;
+	    n?: number;
+	    aspectRatio?: string;
+	    negativePrompt?: string;
+	    personGeneration?: string;
+	    seed?: number;
 	    options?: Record<string, any>;
 	
 	    static createFrom(source: any = {}) {
@@ -65,28 +117,181 @@ export namespace ai {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
 	        this.projectId = source["projectId"];
 	        this.prompt = source["prompt"];
 	        this.images = source["images"];
 	        this.videos = source["videos"];
 	        this.audios = source["audios"];
+	        this.maskAssetId = source["maskAssetId"];
 	        this.model = source["model"];
 	        this.providerId = source["providerId"];
 	        this.size = source["size"];
 	        this.quality = source["quality"];
 	        this.style = source["style"];
+	        this.n = source["n"];
+	        this.aspectRatio = source["aspectRatio"];
+	        this.negativePrompt = source["negativePrompt"];
+	        this.personGeneration = source["personGeneration"];
+	        this.seed = source["seed"];
 	        this.options = source["options"];
 	    }
 	}
+	export class JobInfo {
+	    id: string;
+	    kind: string;
+	    providerId: number;
+	    model: string;
+	    projectId?: number;
+	    // Go type: time
+	    startedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new JobInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.kind = source["kind"];
+	        this.providerId = source["providerId"];
+	        this.model = source["model"];
+	        this.projectId = source["projectId"];
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ToolResult {
+	    callId: string;
+	    name: string;
+	    content: string;
+	    isError?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.callId = source["callId"];
+	        this.name = source["name"];
+	        this.content = source["content"];
+	        this.isError = source["isError"];
+	    }
+	}
+	export class ToolCall {
+	    id: string;
+	    name: string;
+	    arguments: string;
+	    signature?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ToolCall(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.arguments = source["arguments"];
+	        this.signature = source["signature"];
+	    }
+	}
+	export class Message {
+	    role: string;
+	    content: string;
+	    images?: string[];
+	    videos?: string[];
+	    audios?: string[];
+	    documents?: string[];
+	    toolCalls?: ToolCall[];
+	    toolResults?: ToolResult[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Message(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.role = source["role"];
+	        this.content = source["content"];
+	        this.images = source["images"];
+	        this.videos = source["videos"];
+	        this.audios = source["audios"];
+	        this.documents = source["documents"];
+	        this.toolCalls = this.convertValues(source["toolCalls"], ToolCall);
+	        this.toolResults = this.convertValues(source["toolResults"], ToolResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelCost {
+	    input: number;
+	    output: number;
+	    cache_read: number;
+	    cache_write?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelCost(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.input = source["input"];
+	        this.output = source["output"];
+	        this.cache_read = source["cache_read"];
+	        this.cache_write = source["cache_write"];
+	    }
+	}
 	export class Model {
 	    id: string;
+	    display_name?: string;
 	    owner?: string;
 	    created?: number;
 	    object?: string;
+	    provider_id?: number;
 	    provider_name?: string;
 	    provider_type?: string;
 	    input?: string[];
 	    output?: string[];
+	    context_window?: number;
+	    output_limit?: number;
+	    cost?: ModelCost;
+	    release_date?: string;
+	    last_updated?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new Model(source);
@@ -95,16 +300,222 @@ export namespace ai {
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
+	        this.display_name = source["display_name"];
 	        this.owner = source["owner"];
 	        this.created = source["created"];
 	        this.object = source["object"];
+	        this.provider_id = source["provider_id"];
 	        this.provider_name = source["provider_name"];
 	        this.provider_type = source["provider_type"];
 	        this.input = source["input"];
 	        this.output = source["output"];
+	        this.context_window = source["context_window"];
+	        this.output_limit = source["output_limit"];
+	        this.cost = this.convertValues(source["cost"], ModelCost);
+	        this.release_date = source["release_date"];
+	        this.last_updated = source["last_updated"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class Parameter {
+	    name: string;
+	    label: string;
+	    description?: string;
+	    type: string;
+	    min?: number;
+	    max?: number;
+	    enum?: any[];
+	    default?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new Parameter(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.label = source["label"];
+	        this.description = source["description"];
+	        this.type = source["type"];
+	        this.min = source["min"];
+	        this.max = source["max"];
+	        this.enum = source["enum"];
+	        this.default = source["default"];
+	    }
+	}
+	export class ResponseFormat {
+	    type: string;
+	    name?: string;
+	    schema?: Record<string, any>;
+	    strict?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ResponseFormat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.type = source["type"];
+	        this.name = source["name"];
+	        this.schema = source["schema"];
+	        this.strict = source["strict"];
+	    }
+	}
+	export class SubtitleRequest {
+	    jobId?: string;
+	    assetId: number;
+	    model: string;
+	    providerId: number;
+	    language?: string;
+	    prompt?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SubtitleRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
+	        this.assetId = source["assetId"];
+	        this.model = source["model"];
+	        this.providerId = source["providerId"];
+	        this.language = source["language"];
+	        this.prompt = source["prompt"];
+	    }
+	}
+	export class TranscriptSegment {
+	    start: number;
+	    end: number;
+	    text: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TranscriptSegment(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.start = source["start"];
+	        this.end = source["end"];
+	        this.text = source["text"];
+	    }
+	}
+	export class TranscribeResponse {
+	    text: string;
+	    language?: string;
+	    duration?: number;
+	    segments?: TranscriptSegment[];
+	    promptTokens?: number;
+	    outputTokens?: number;
+	    model: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TranscribeResponse(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.text = source["text"];
+	        this.language = source["language"];
+	        this.duration = source["duration"];
+	        this.segments = this.convertValues(source["segments"], TranscriptSegment);
+	        this.promptTokens = source["promptTokens"];
+	        this.outputTokens = source["outputTokens"];
+	        this.model = source["model"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SubtitleResult {
+	    jobId?: string;
+	    srt?: database.Asset;
+	    vtt?: database.Asset;
+	    transcript?: TranscribeResponse;
+	
+	    static createFrom(source: any = {}) {
+	        return new SubtitleResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
+	        this.srt = this.convertValues(source["srt"], database.Asset);
+	        this.vtt = this.convertValues(source["vtt"], database.Asset);
+	        this.transcript = this.convertValues(source["transcript"], TranscribeResponse);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Tool {
+	    name: string;
+	    description?: string;
+	    parameters?: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new Tool(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.parameters = source["parameters"];
 	    }
 	}
 	export class TextRequest {
+	    jobId?: string;
+	    projectId?: number;
+	    system?: string;
+	    messages?: Message[];
 	    prompt: string;
 	    images?: string[];
 	    videos?: string[];
@@ -115,6 +526,9 @@ export namespace ai {
 	    temperature?: number;
 	    maxTokens?: number;
 	    options?: Record<string, any>;
+	    responseFormat?: ResponseFormat;
+	    tools?: Tool[];
+	    toolChoice?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new TextRequest(source);
@@ -122,6 +536,10 @@ export namespace ai {
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
+	        this.projectId = source["projectId"];
+	        this.system = source["system"];
+	        this.messages = this.convertValues(source["messages"], Message);
 	        this.prompt = source["prompt"];
 	        this.images = source["images"];
 	        this.videos = source["videos"];
@@ -132,37 +550,228 @@ export namespace ai {
 	        this.temperature = source["temperature"];
 	        this.maxTokens = source["maxTokens"];
 	        this.options = source["options"];
+	        this.responseFormat = this.convertValues(source["responseFormat"], ResponseFormat);
+	        this.tools = this.convertValues(source["tools"], Tool);
+	        this.toolChoice = source["toolChoice"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class TranscribeRequest {
+	    jobId?: string;
+	    projectId?: number;
+	    media: string;
+	    model: string;
+	    providerId: number;
+	    language?: string;
+	    prompt?: string;
+	    options?: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new TranscribeRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
+	        this.projectId = source["projectId"];
+	        this.media = source["media"];
+	        this.model = source["model"];
+	        this.providerId = source["providerId"];
+	        this.language = source["language"];
+	        this.prompt = source["prompt"];
+	        this.options = source["options"];
+	    }
+	}
+	
+	
+	export class VideoRequest {
+	    jobId?: string;
+	    projectId?: number;
+	    prompt: string;
+	    images?: string[];
+	    videos?: string[];
+	    audios?: string[];
+	    model: string;
+	    providerId: number;
+	    duration?: string;
+	    resolution?: string;
+	    aspectRatio?: string;
+	    negativePrompt?: string;
+	    seed?: number;
+	    generateAudio?: boolean;
+	    n?: number;
+	    options?: Record<string, any>;
+	
+	    static createFrom(source: any = {}) {
+	        return new VideoRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.jobId = source["jobId"];
+	        this.projectId = source["projectId"];
+	        this.prompt = source["prompt"];
+	        this.images = source["images"];
+	        this.videos = source["videos"];
+	        this.audios = source["audios"];
+	        this.model = source["model"];
+	        this.providerId = source["providerId"];
+	        this.duration = source["duration"];
+	        this.resolution = source["resolution"];
+	        this.aspectRatio = source["aspectRatio"];
+	        this.negativePrompt = source["negativePrompt"];
+	        this.seed = source["seed"];
+	        this.generateAudio = source["generateAudio"];
+	        this.n = source["n"];
+	        this.options = source["options"];
+	    }
+	}
+	export class WorkflowEdge {
+	    id: string;
+	    source: string;
+	    target: string;
+	    animated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowEdge(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.source = source["source"];
+	        this.target = source["target"];
+	        this.animated = source["animated"];
+	    }
+	}
+	export class WorkflowNodeData {
+	    label: string;
+	    type: string;
+	    prompt?: string;
+	    modelId?: string;
+	    providerId?: number;
+	    projectId?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowNodeData(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.label = source["label"];
+	        this.type = source["type"];
+	        this.prompt = source["prompt"];
+	        this.modelId = source["modelId"];
+	        this.providerId = source["providerId"];
+	        this.projectId = source["projectId"];
+	    }
+	}
+	export class WorkflowPosition {
+	    x: number;
+	    y: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowPosition(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.x = source["x"];
+	        this.y = source["y"];
+	    }
+	}
+	export class WorkflowNode {
+	    id: string;
+	    type: string;
+	    position: WorkflowPosition;
+	    data: WorkflowNodeData;
+	
+	    static createFrom(source: any = {}) {
+	        return new WorkflowNode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.type = source["type"];
+	        this.position = this.convertValues(source["position"], WorkflowPosition);
+	        this.data = this.convertValues(source["data"], WorkflowNodeData);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class VideoRequest {
-	    projectId?: number;
-	    prompt: string;
-	    images?: string[];
-	    videos?: string[];
-	    audios?: string[];
-	    model: string;
-	    providerId: number;
-	    duration?: string;
-	    resolution?: string;
-	    options?: Record<string, any>;
+	
+	export class WorkflowPlan {
+	    nodes: WorkflowNode[];
+	    edges: WorkflowEdge[];
+	    warnings?: string[];
+	    usage?: Record<string, any>;
 	
 	    static createFrom(source: any = {}) {
-	        return new VideoRequest(source);
+	        return new WorkflowPlan(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.projectId = source["projectId"];
-	        this.prompt = source["prompt"];
-	        this.images = source["images"];
-	        this.videos = source["videos"];
-	        this.audios = source["audios"];
-	        this.model = source["model"];
-	        this.providerId = source["providerId"];
-	        this.duration = source["duration"];
-	        this.resolution = source["resolution"];
-	        this.options = source["options"];
+	        this.nodes = this.convertValues(source["nodes"], WorkflowNode);
+	        this.edges = this.convertValues(source["edges"], WorkflowEdge);
+	        this.warnings = source["warnings"];
+	        this.usage = source["usage"];
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 
 }
@@ -204,6 +813,9 @@ export namespace database {
 	    url: string;
 	    isUserProvided: boolean;
 	    md5: string;
+	    prompt?: string;
+	    sourceAssetId?: number;
+	    generationId?: string;
 	    // Go type: time
 	    createdAt: any;
 	    // Go type: time
@@ -222,6 +834,217 @@ export namespace database {
 	        this.url = source["url"];
 	        this.isUserProvided = source["isUserProvided"];
 	        this.md5 = source["md5"];
+	        this.prompt = source["prompt"];
+	        this.sourceAssetId = source["sourceAssetId"];
+	        this.generationId = source["generationId"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class AssetMatch {
+	    id: number;
+	    projectId: number;
+	    type: string;
+	    path: string;
+	    url: string;
+	    isUserProvided: boolean;
+	    md5: string;
+	    prompt?: string;
+	    sourceAssetId?: number;
+	    generationId?: string;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	    score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AssetMatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.projectId = source["projectId"];
+	        this.type = source["type"];
+	        this.path = source["path"];
+	        this.url = source["url"];
+	        this.isUserProvided = source["isUserProvided"];
+	        this.md5 = source["md5"];
+	        this.prompt = source["prompt"];
+	        this.sourceAssetId = source["sourceAssetId"];
+	        this.generationId = source["generationId"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	        this.score = source["score"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class BudgetStatus {
+	    id: number;
+	    scope: string;
+	    scopeId: number;
+	    period: string;
+	    limit: number;
+	    action: string;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	    spent: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BudgetStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.scope = source["scope"];
+	        this.scopeId = source["scopeId"];
+	        this.period = source["period"];
+	        this.limit = source["limit"];
+	        this.action = source["action"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	        this.spent = source["spent"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GenerationJob {
+	    id: number;
+	    jobId: string;
+	    projectId: number;
+	    providerId: number;
+	    providerJobId: string;
+	    kind: string;
+	    model: string;
+	    status: string;
+	    request: string;
+	    resultAssetId: number;
+	    error: string;
+	    usageRecorded: boolean;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new GenerationJob(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.jobId = source["jobId"];
+	        this.projectId = source["projectId"];
+	        this.providerId = source["providerId"];
+	        this.providerJobId = source["providerJobId"];
+	        this.kind = source["kind"];
+	        this.model = source["model"];
+	        this.status = source["status"];
+	        this.request = source["request"];
+	        this.resultAssetId = source["resultAssetId"];
+	        this.error = source["error"];
+	        this.usageRecorded = source["usageRecorded"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelOverride {
+	    id: number;
+	    providerId: number;
+	    modelId: string;
+	    displayName: string;
+	    input: string[];
+	    output: string[];
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelOverride(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.providerId = source["providerId"];
+	        this.modelId = source["modelId"];
+	        this.displayName = source["displayName"];
+	        this.input = source["input"];
+	        this.output = source["output"];
 	        this.createdAt = this.convertValues(source["createdAt"], null);
 	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
@@ -250,6 +1073,9 @@ export namespace database {
 	    type: string;
 	    apiKey: string;
 	    baseUrl: string;
+	    retryMaxAttempts: number;
+	    retryBudgetSeconds: number;
+	    embeddingModel: string;
 	    // Go type: time
 	    createdAt: any;
 	    // Go type: time
@@ -266,6 +1092,9 @@ export namespace database {
 	        this.type = source["type"];
 	        this.apiKey = source["apiKey"];
 	        this.baseUrl = source["baseUrl"];
+	        this.retryMaxAttempts = source["retryMaxAttempts"];
+	        this.retryBudgetSeconds = source["retryBudgetSeconds"];
+	        this.embeddingModel = source["embeddingModel"];
 	        this.createdAt = this.convertValues(source["createdAt"], null);
 	        this.updatedAt = this.convertValues(source["updatedAt"], null);
 	    }
@@ -332,6 +1161,80 @@ export namespace database {
 		    return a;
 		}
 	}
+	export class SpendingBudget {
+	    id: number;
+	    scope: string;
+	    scopeId: number;
+	    period: string;
+	    limit: number;
+	    action: string;
+	    // Go type: time
+	    createdAt: any;
+	    // Go type: time
+	    updatedAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new SpendingBudget(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.scope = source["scope"];
+	        this.scopeId = source["scopeId"];
+	        this.period = source["period"];
+	        this.limit = source["limit"];
+	        this.action = source["action"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.updatedAt = this.convertValues(source["updatedAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UsageSummary {
+	    key: string;
+	    label: string;
+	    calls: number;
+	    promptTokens: number;
+	    outputTokens: number;
+	    totalTokens: number;
+	    count: number;
+	    cost: number;
+	    unpriced: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new UsageSummary(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.key = source["key"];
+	        this.label = source["label"];
+	        this.calls = source["calls"];
+	        this.promptTokens = source["promptTokens"];
+	        this.outputTokens = source["outputTokens"];
+	        this.totalTokens = source["totalTokens"];
+	        this.count = source["count"];
+	        this.cost = source["cost"];
+	        this.unpriced = source["unpriced"];
+	    }
+	}
 
 }
 
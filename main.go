@@ -67,6 +67,13 @@ func main() {
 		HideWindowOnClose: true,
 		OnStartup: func(ctx context.Context) {
 			bindingApp.WailsContext = &ctx
+
+			// Pick up video generations that were still running when the app last stopped
+			if initErr == "" {
+				if err := aiService.ResumeGenerationJobs(); err != nil {
+					println("Error resuming generation jobs:", err.Error())
+				}
+			}
 		},
 		SingleInstanceLock: &options.SingleInstanceLock{
 			UniqueId: "3e347bce-745e-4dd3-a6de-c6e6e2a44c86",
@@ -1,8 +1,10 @@
 package ai
 
 import (
+	"context"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"visionflow/storage"
 )
@@ -17,9 +20,36 @@ import (
 //go:embed model_data.json
 var modelDataJSON []byte
 
+// modelDataMetaJSON records when the embedded model_data.json was fetched, update it together with the data
+//
+//go:embed model_data.meta.json
+var modelDataMetaJSON []byte
+
 type ModelCapabilities struct {
-	Input  []string `json:"input"`
-	Output []string `json:"output"`
+	Input       []string    `json:"input"`
+	Output      []string    `json:"output"`
+	Cost        *ModelCost  `json:"cost,omitempty"`
+	Limit       *ModelLimit `json:"limit,omitempty"`
+	ReleaseDate string      `json:"release_date,omitempty"`
+	LastUpdated string      `json:"last_updated,omitempty"`
+}
+
+// ModelCost is the models.dev price in USD per million tokens
+type ModelCost struct {
+	Input      float64 `json:"input"`
+	Output     float64 `json:"output"`
+	CacheRead  float64 `json:"cache_read"`
+	CacheWrite float64 `json:"cache_write,omitempty"`
+}
+
+// ModelLimit is the models.dev token limit of a model
+type ModelLimit struct {
+	Context int `json:"context"` // Context window, input and output together
+	Output  int `json:"output"`  // Most tokens a single response may hold
+}
+
+func (c *ModelCost) hasPrice() bool {
+	return c != nil && (c.Input > 0 || c.Output > 0)
 }
 
 type ModelInfo struct {
@@ -28,136 +58,361 @@ type ModelInfo struct {
 		Input  []string `json:"input"`
 		Output []string `json:"output"`
 	} `json:"modalities"`
+	Cost        *ModelCost  `json:"cost"`
+	Limit       *ModelLimit `json:"limit"`
+	ReleaseDate string      `json:"release_date"`
+	LastUpdated string      `json:"last_updated"`
 }
 
 type ProviderInfo struct {
 	Models map[string]ModelInfo `json:"models"`
 }
 
+const (
+	modelDataURL      = "https://models.dev/api.json"
+	modelDataFile     = "model_data.json"
+	modelDataMetaFile = "model_data.meta.json"
+)
+
+// Capability data sources reported by CapabilitiesStatus
+const (
+	CapabilitiesEmbedded = "embedded"
+	CapabilitiesDisk     = "disk"
+	CapabilitiesRemote   = "remote"
+)
+
+// CapabilitiesStatus describes the model data currently in use
+type CapabilitiesStatus struct {
+	Source     string     `json:"source"`              // Where the loaded data came from
+	Models     int        `json:"models"`              // Number of model entries across providers
+	FetchedAt  *time.Time `json:"fetchedAt,omitempty"` // When models.dev last confirmed the data
+	LastError  string     `json:"lastError,omitempty"` // Error of the last refresh, if it failed
+	Refreshing bool       `json:"refreshing"`
+}
+
+// modelDataMeta is stored next to the data file so later refreshes can be conditional
+type modelDataMeta struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+}
+
 var (
-	capabilitiesMap   map[string]ModelCapabilities
-	capabilitiesMutex sync.RWMutex
-	once              sync.Once
+	capabilitiesMap    map[string]ModelCapabilities
+	capabilitiesStatus = CapabilitiesStatus{Source: CapabilitiesEmbedded}
+	capabilitiesMeta   *modelDataMeta
+	capabilitiesMutex  sync.RWMutex
+	once               sync.Once
+
+	// refreshMutex keeps refreshes from running concurrently
+	refreshMutex sync.Mutex
+	// modelDataClient bounds how long a models.dev fetch may take
+	modelDataClient = &http.Client{Timeout: 60 * time.Second}
 )
 
 // InitCapabilities initializes the model capabilities data.
-// It writes the embedded data to the config directory and then updates it from the web.
+// It loads whichever is newer of the data saved by the last refresh and the embedded copy,
+// then refreshes it from models.dev in the background.
 func InitCapabilities() error {
 	path, err := storage.GetAppConfigDir()
 	if err != nil {
 		return err
 	}
-	modelDataPath := filepath.Join(path, "model_data.json")
 
-	// 1. Write embedded data to file (bootstrap/ensure existence)
-	// We do this to ensure we have value in the file system for inspection or fallback,
-	// and to "seed" the file as requested.
-	_ = os.WriteFile(modelDataPath, modelDataJSON, 0644)
+	source, models, meta := CapabilitiesEmbedded, 0, (*modelDataMeta)(nil)
+	data, diskMeta, modifiedAt, err := readModelData(path)
+	if err != nil {
+		fmt.Printf("Failed to read saved model data: %v\n", err)
+	}
+	if data != nil && modifiedAt.After(embeddedDataTime()) {
+		if caps, count, err := parseCapabilities(data); err != nil {
+			fmt.Printf("Ignoring invalid saved model data: %v\n", err)
+		} else {
+			setCapabilities(caps)
+			source, models, meta = CapabilitiesDisk, count, diskMeta
+		}
+	}
+	if source == CapabilitiesEmbedded {
+		caps, count, err := parseCapabilities(modelDataJSON)
+		if err != nil {
+			return fmt.Errorf("invalid embedded model data: %w", err)
+		}
+		setCapabilities(caps)
+		models = count
+	}
 
-	// 2. Load initial data into memory from the embedded content (guaranteed to be there)
-	// Alternatively we could read from file, but embedded is safer for first run.
-	loadCapabilitiesFromBytes(modelDataJSON)
+	capabilitiesMutex.Lock()
+	capabilitiesMeta = meta
+	capabilitiesStatus = CapabilitiesStatus{Source: source, Models: models}
+	if meta != nil && !meta.FetchedAt.IsZero() {
+		fetchedAt := meta.FetchedAt
+		capabilitiesStatus.FetchedAt = &fetchedAt
+	}
+	capabilitiesMutex.Unlock()
 
-	// 3. Background update
+	// Background update
 	go func() {
-		updateModelConfigs(modelDataPath)
+		if _, err := RefreshCapabilities(context.Background()); err != nil {
+			fmt.Printf("Failed to refresh model data from models.dev: %v\n", err)
+		}
 	}()
 
 	return nil
 }
 
-func updateModelConfigs(filePath string) {
-	resp, err := http.Get("https://models.dev/api.json")
+// readModelData reads the saved data file with its metadata and when it was last confirmed current.
+// The data is nil when no refresh has been saved yet.
+func readModelData(dir string) ([]byte, *modelDataMeta, time.Time, error) {
+	dataPath := filepath.Join(dir, modelDataFile)
+	info, err := os.Stat(dataPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	data, err := os.ReadFile(dataPath)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+
+	// Files saved before the metadata existed only have their modification time
+	modifiedAt := info.ModTime()
+	var meta *modelDataMeta
+	if raw, err := os.ReadFile(filepath.Join(dir, modelDataMetaFile)); err == nil {
+		var m modelDataMeta
+		if err := json.Unmarshal(raw, &m); err != nil {
+			fmt.Printf("Ignoring invalid model data metadata: %v\n", err)
+		} else {
+			meta = &m
+			if !m.FetchedAt.IsZero() {
+				modifiedAt = m.FetchedAt
+			}
+		}
+	}
+	return data, meta, modifiedAt, nil
+}
+
+// embeddedDataTime is when the embedded data was fetched from models.dev,
+// so data saved before an upgrade does not shadow the newer copy shipped with it
+func embeddedDataTime() time.Time {
+	var meta modelDataMeta
+	if err := json.Unmarshal(modelDataMetaJSON, &meta); err != nil {
+		fmt.Printf("Invalid embedded model data metadata: %v\n", err)
+		return time.Time{}
+	}
+	return meta.FetchedAt
+}
+
+// GetCapabilitiesStatus reports where the model data in use came from and when it was last refreshed
+func GetCapabilitiesStatus() CapabilitiesStatus {
+	capabilitiesMutex.RLock()
+	defer capabilitiesMutex.RUnlock()
+	return capabilitiesStatus
+}
+
+// RefreshCapabilities fetches the model data from models.dev, sending the validators of the saved copy
+// so an unchanged list is not downloaded again. New data is saved to the config directory and loaded.
+func RefreshCapabilities(ctx context.Context) (CapabilitiesStatus, error) {
+	refreshMutex.Lock()
+	defer refreshMutex.Unlock()
+
+	capabilitiesMutex.Lock()
+	capabilitiesStatus.Refreshing = true
+	capabilitiesMutex.Unlock()
+
+	err := refreshCapabilities(ctx)
+
+	capabilitiesMutex.Lock()
+	defer capabilitiesMutex.Unlock()
+	capabilitiesStatus.Refreshing = false
+	capabilitiesStatus.LastError = ""
+	if err != nil {
+		capabilitiesStatus.LastError = err.Error()
+	}
+	return capabilitiesStatus, err
+}
+
+func refreshCapabilities(ctx context.Context) error {
+	dir, err := storage.GetAppConfigDir()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, modelDataURL, nil)
+	if err != nil {
+		return err
+	}
+
+	// Validators only describe the saved file, so they are useless while the embedded copy is loaded
+	capabilitiesMutex.RLock()
+	var meta modelDataMeta
+	if capabilitiesMeta != nil && capabilitiesStatus.Source != CapabilitiesEmbedded {
+		meta = *capabilitiesMeta
+	}
+	capabilitiesMutex.RUnlock()
+	if meta.ETag != "" {
+		req.Header.Set("If-None-Match", meta.ETag)
+	}
+	if meta.LastModified != "" {
+		req.Header.Set("If-Modified-Since", meta.LastModified)
+	}
+
+	resp, err := modelDataClient.Do(req)
 	if err != nil {
-		fmt.Printf("Failed to fetch model data from models.dev: %v\n", err)
-		return
+		return fmt.Errorf("failed to fetch model data: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Failed to fetch model data: status %d\n", resp.StatusCode)
-		return
+	now := time.Now()
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		meta.FetchedAt = now
+		if err := writeModelDataMeta(dir, meta); err != nil {
+			fmt.Printf("Failed to write model data metadata: %v\n", err)
+		}
+		capabilitiesMutex.Lock()
+		capabilitiesMeta = &meta
+		capabilitiesStatus.FetchedAt = &now
+		capabilitiesMutex.Unlock()
+		return nil
+	case http.StatusOK:
+	default:
+		return fmt.Errorf("failed to fetch model data: status %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		fmt.Printf("Failed to read model data body: %v\n", err)
-		return
+		return fmt.Errorf("failed to read model data body: %w", err)
 	}
-
-	// Validate JSON schema roughly by unmarshalling
-	var data map[string]ProviderInfo
-	if err := json.Unmarshal(body, &data); err != nil {
-		fmt.Printf("Invalid model data JSON from remote: %v\n", err)
-		return
+	caps, models, err := parseCapabilities(body)
+	if err != nil {
+		return fmt.Errorf("invalid model data JSON from remote: %w", err)
 	}
 
-	// Write updated data to file
-	if err := os.WriteFile(filePath, body, 0644); err != nil {
+	meta = modelDataMeta{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    now,
+	}
+	// Without the data on disk the validators would claim a copy that is not there
+	if err := writeFileAtomic(filepath.Join(dir, modelDataFile), body); err != nil {
 		fmt.Printf("Failed to write updated model data: %v\n", err)
+	} else if err := writeModelDataMeta(dir, meta); err != nil {
+		fmt.Printf("Failed to write model data metadata: %v\n", err)
 	}
 
-	// Update memory
-	loadCapabilitiesFromBytes(body)
+	setCapabilities(caps)
+	capabilitiesMutex.Lock()
+	capabilitiesMeta = &meta
+	capabilitiesStatus.Source = CapabilitiesRemote
+	capabilitiesStatus.Models = models
+	capabilitiesStatus.FetchedAt = &now
+	capabilitiesMutex.Unlock()
+	return nil
+}
+
+func writeModelDataMeta(dir string, meta modelDataMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, modelDataMetaFile), data)
 }
 
-func loadCapabilitiesFromBytes(dataBytes []byte) {
+// writeFileAtomic replaces a file through a temporary copy, so a crash never leaves half a file behind
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func setCapabilities(caps map[string]ModelCapabilities) {
 	capabilitiesMutex.Lock()
 	defer capabilitiesMutex.Unlock()
+	capabilitiesMap = caps
+}
 
-	capabilitiesMap = make(map[string]ModelCapabilities)
+// parseCapabilities indexes models.dev data by model ID and returns how many models it lists
+func parseCapabilities(dataBytes []byte) (map[string]ModelCapabilities, int, error) {
 	var data map[string]ProviderInfo
 	if err := json.Unmarshal(dataBytes, &data); err != nil {
-		return
+		return nil, 0, err
+	}
+
+	byID := make(map[string]ModelCapabilities)
+	models := 0
+
+	// The same model is listed by several providers (e.g. resellers with missing or zero pricing),
+	// so never let an entry without a price replace one that has it
+	store := func(key string, caps ModelCapabilities) {
+		if existing, ok := byID[key]; ok && existing.Cost.hasPrice() && !caps.Cost.hasPrice() {
+			return
+		}
+		byID[key] = caps
 	}
 
 	for _, provider := range data {
+		models += len(provider.Models)
 		for id, model := range provider.Models {
-			// Store by the ID in the JSON (key)
-			capabilitiesMap[id] = ModelCapabilities{
-				Input:  model.Modalities.Input,
-				Output: model.Modalities.Output,
+			caps := ModelCapabilities{
+				Input:       model.Modalities.Input,
+				Output:      model.Modalities.Output,
+				Cost:        model.Cost,
+				Limit:       model.Limit,
+				ReleaseDate: model.ReleaseDate,
+				LastUpdated: model.LastUpdated,
 			}
+
+			// Store by the ID in the JSON (key)
+			store(id, caps)
 			// Store by the "id" field if different
 			if model.ID != "" && model.ID != id {
-				capabilitiesMap[model.ID] = ModelCapabilities{
-					Input:  model.Modalities.Input,
-					Output: model.Modalities.Output,
-				}
+				store(model.ID, caps)
 			}
 
 			// Also index by base name (everything after the last /) to handle namespaces
 			if lastSlash := strings.LastIndex(id, "/"); lastSlash != -1 {
 				baseName := id[lastSlash+1:]
 				if baseName != "" {
-					capabilitiesMap[baseName] = ModelCapabilities{
-						Input:  model.Modalities.Input,
-						Output: model.Modalities.Output,
-					}
+					store(baseName, caps)
 				}
 			}
 			if model.ID != "" {
 				if lastSlash := strings.LastIndex(model.ID, "/"); lastSlash != -1 {
 					baseName := model.ID[lastSlash+1:]
 					if baseName != "" && baseName != model.ID {
-						capabilitiesMap[baseName] = ModelCapabilities{
-							Input:  model.Modalities.Input,
-							Output: model.Modalities.Output,
-						}
+						store(baseName, caps)
 					}
 				}
 			}
 		}
 	}
+	return byID, models, nil
 }
 
-// GetModelCapabilities returns the input and output modalities for a given model ID.
-// If not found, returns empty slices.
-func GetModelCapabilities(modelID string) (input, output []string) {
+// lookupCapabilities finds the models.dev entry for a model ID, ignoring case as a fallback
+func lookupCapabilities(modelID string) (ModelCapabilities, bool) {
 	// Ensure loaded at least once if InitCapabilities wasn't called (e.g. in tests)
 	once.Do(func() {
-		if capabilitiesMap == nil {
-			loadCapabilitiesFromBytes(modelDataJSON)
+		capabilitiesMutex.RLock()
+		loaded := capabilitiesMap != nil
+		capabilitiesMutex.RUnlock()
+		if !loaded {
+			if caps, _, err := parseCapabilities(modelDataJSON); err == nil {
+				setCapabilities(caps)
+			}
 		}
 	})
 
@@ -166,15 +421,31 @@ func GetModelCapabilities(modelID string) (input, output []string) {
 
 	// Exact match
 	if caps, ok := capabilitiesMap[modelID]; ok {
-		return caps.Input, caps.Output
+		return caps, true
 	}
 
 	// Fallback: try case-insensitive
 	for id, caps := range capabilitiesMap {
 		if strings.EqualFold(id, modelID) {
-			return caps.Input, caps.Output
+			return caps, true
 		}
 	}
 
-	return nil, nil
+	return ModelCapabilities{}, false
+}
+
+// GetModelCapabilities returns the input and output modalities for a given model ID.
+// If not found, returns empty slices.
+func GetModelCapabilities(modelID string) (input, output []string) {
+	caps, _ := lookupCapabilities(modelID)
+	return caps.Input, caps.Output
+}
+
+// GetModelCost returns the models.dev token price for a model ID, or nil if it is unknown
+func GetModelCost(modelID string) *ModelCost {
+	caps, ok := lookupCapabilities(strings.TrimPrefix(modelID, "models/"))
+	if !ok {
+		return nil
+	}
+	return caps.Cost
 }
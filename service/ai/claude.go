@@ -3,6 +3,7 @@ package ai
 import (
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"mime"
@@ -20,6 +21,7 @@ import (
 type ClaudeClient struct {
 	client anthropic.Client
 	config database.ModelProvider
+	retry  RetryPolicy
 }
 
 // NewClaudeClient creates a new Anthropic Claude client
@@ -30,6 +32,9 @@ func NewClaudeClient(config database.ModelProvider) (*ClaudeClient, error) {
 
 	opts := []option.RequestOption{
 		option.WithAPIKey(config.APIKey),
+		option.WithHTTPClient(newHTTPClient()),
+		// Retries are handled by withRetry so every provider follows the same policy
+		option.WithMaxRetries(0),
 	}
 
 	if config.BaseURL != "" {
@@ -41,28 +46,145 @@ func NewClaudeClient(config database.ModelProvider) (*ClaudeClient, error) {
 	return &ClaudeClient{
 		client: client,
 		config: config,
+		retry:  retryPolicyFor(config),
 	}, nil
 }
 
-// GenerateText generates text using Claude's messages API
-func (c *ClaudeClient) GenerateText(ctx context.Context, req TextGenerateRequest) (*TextGenerateResponse, error) {
-	if req.Model == "" {
-		req.Model = "claude-3-5-sonnet-20241022"
+// buildMessageParams maps a TextGenerateRequest onto Claude message parameters
+func (c *ClaudeClient) buildMessageParams(req TextGenerateRequest) (anthropic.MessageNewParams, error) {
+	if err := req.ResponseFormat.validate(); err != nil {
+		return anthropic.MessageNewParams{}, err
+	}
+	if err := req.validateTools(); err != nil {
+		return anthropic.MessageNewParams{}, err
+	}
+	// Structured output already occupies the tool choice
+	if req.ResponseFormat.structured() && len(req.Tools) > 0 {
+		return anthropic.MessageNewParams{}, errors.New("Claude does not support structured output together with tools")
+	}
+
+	options, err := resolveOptions(database.ProviderClaude, req.Model, req.Options)
+	if err != nil {
+		return anthropic.MessageNewParams{}, err
 	}
+	thinkingBudget, _ := options.int("thinking_budget")
 
 	maxTokens := int64(4096)
 	if req.MaxTokens != nil {
 		maxTokens = int64(*req.MaxTokens)
 	}
+	if thinkingBudget > 0 {
+		if err := validateClaudeThinking(req, options, thinkingBudget); err != nil {
+			return anthropic.MessageNewParams{}, err
+		}
+		// The budget is part of max_tokens, the answer needs room on top of it
+		if req.MaxTokens == nil {
+			maxTokens += int64(thinkingBudget)
+		} else if maxTokens <= int64(thinkingBudget) {
+			return anthropic.MessageNewParams{}, fmt.Errorf("max tokens (%d) must be larger than the thinking budget (%d)", maxTokens, thinkingBudget)
+		}
+	}
+
+	conversation, err := req.conversation()
+	if err != nil {
+		return anthropic.MessageNewParams{}, err
+	}
+
+	messages := make([]anthropic.MessageParam, 0, len(conversation))
+	for _, msg := range conversation {
+		message, err := claudeMessage(msg)
+		if err != nil {
+			return anthropic.MessageNewParams{}, err
+		}
+		messages = append(messages, message)
+	}
+
+	messageReq := anthropic.MessageNewParams{
+		Model:     anthropic.Model(req.Model),
+		MaxTokens: maxTokens,
+		Messages:  messages,
+	}
+
+	if req.System != "" {
+		messageReq.System = []anthropic.TextBlockParam{{Text: req.System}}
+	}
+
+	if req.Temperature != nil {
+		messageReq.Temperature = param.NewOpt(float64(*req.Temperature))
+	}
+	if topP, ok := options.float("top_p"); ok {
+		messageReq.TopP = param.NewOpt(topP)
+	}
+	if topK, ok := options.int("top_k"); ok {
+		messageReq.TopK = param.NewOpt(int64(topK))
+	}
+	if thinkingBudget > 0 {
+		messageReq.Thinking = anthropic.ThinkingConfigParamOfEnabled(int64(thinkingBudget))
+	}
+
+	// Claude has no JSON mode, forcing a tool whose input is the schema gives the same guarantee
+	if format := req.ResponseFormat; format.structured() {
+		messageReq.Tools = []anthropic.ToolUnionParam{{OfTool: claudeStructuredTool(format)}}
+		messageReq.ToolChoice = anthropic.ToolChoiceParamOfTool(format.schemaName())
+	}
 
-	contentBlocks := []anthropic.ContentBlockParamUnion{
-		anthropic.NewTextBlock(req.Prompt),
+	for _, tool := range req.Tools {
+		toolParam := &anthropic.ToolParam{
+			Name:        tool.Name,
+			InputSchema: claudeInputSchema(tool.toolParameters()),
+		}
+		if tool.Description != "" {
+			toolParam.Description = param.NewOpt(tool.Description)
+		}
+		messageReq.Tools = append(messageReq.Tools, anthropic.ToolUnionParam{OfTool: toolParam})
+	}
+	if len(req.Tools) > 0 {
+		switch req.ToolChoice {
+		case "":
+		case ToolChoiceAuto:
+			messageReq.ToolChoice = anthropic.ToolChoiceUnionParam{OfAuto: &anthropic.ToolChoiceAutoParam{}}
+		case ToolChoiceNone:
+			none := anthropic.NewToolChoiceNoneParam()
+			messageReq.ToolChoice = anthropic.ToolChoiceUnionParam{OfNone: &none}
+		case ToolChoiceRequired:
+			messageReq.ToolChoice = anthropic.ToolChoiceUnionParam{OfAny: &anthropic.ToolChoiceAnyParam{}}
+		default:
+			messageReq.ToolChoice = anthropic.ToolChoiceParamOfTool(req.ToolChoice)
+		}
 	}
 
-	for _, imgPath := range req.Images {
+	return messageReq, nil
+}
+
+// claudeMessage maps a conversation turn onto a Claude message, images and PDF documents are sent inline as base64
+func claudeMessage(msg Message) (anthropic.MessageParam, error) {
+	var contentBlocks []anthropic.ContentBlockParamUnion
+	// Tool results go back in a user turn
+	if msg.Role == RoleTool {
+		for _, result := range msg.ToolResults {
+			contentBlocks = append(contentBlocks, anthropic.NewToolResultBlock(result.CallID, result.Content, result.IsError))
+		}
+		return anthropic.NewUserMessage(contentBlocks...), nil
+	}
+
+	if msg.Content != "" || (len(msg.Images) == 0 && len(msg.Documents) == 0 && len(msg.ToolCalls) == 0) {
+		contentBlocks = append(contentBlocks, anthropic.NewTextBlock(msg.Content))
+	}
+
+	for _, docPath := range msg.Documents {
+		data, err := LoadContent(docPath)
+		if err != nil {
+			return anthropic.MessageParam{}, err
+		}
+		contentBlocks = append(contentBlocks, anthropic.NewDocumentBlock(anthropic.Base64PDFSourceParam{
+			Data: base64.StdEncoding.EncodeToString(data),
+		}))
+	}
+
+	for _, imgPath := range msg.Images {
 		data, err := LoadContent(imgPath)
 		if err != nil {
-			return nil, err
+			return anthropic.MessageParam{}, err
 		}
 
 		ext := filepath.Ext(imgPath)
@@ -78,19 +200,155 @@ func (c *ClaudeClient) GenerateText(ctx context.Context, req TextGenerateRequest
 		contentBlocks = append(contentBlocks, anthropic.NewImageBlockBase64(mimeType, b64Data))
 	}
 
-	messageReq := anthropic.MessageNewParams{
-		Model:     anthropic.Model(req.Model),
-		MaxTokens: maxTokens,
-		Messages: []anthropic.MessageParam{
-			anthropic.NewUserMessage(contentBlocks...),
-		},
+	for _, call := range msg.ToolCalls {
+		input := json.RawMessage(call.Arguments)
+		if len(input) == 0 {
+			input = json.RawMessage("{}")
+		}
+		contentBlocks = append(contentBlocks, anthropic.NewToolUseBlock(call.ID, input, call.Name))
+	}
+
+	if msg.Role == RoleAssistant {
+		return anthropic.NewAssistantMessage(contentBlocks...), nil
+	}
+	return anthropic.NewUserMessage(contentBlocks...), nil
+}
+
+// claudeWrapsSchema reports whether the schema root is not an object and must be wrapped,
+// tool inputs are always objects.
+func claudeWrapsSchema(format *ResponseFormat) bool {
+	return len(format.Schema) > 0 && format.Schema["type"] != "object"
+}
+
+func claudeStructuredTool(format *ResponseFormat) *anthropic.ToolParam {
+	schema := format.Schema
+	if claudeWrapsSchema(format) {
+		schema = map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"result": format.Schema},
+			"required":   []string{"result"},
+		}
+	}
+
+	return &anthropic.ToolParam{
+		Name:        format.schemaName(),
+		Description: param.NewOpt("Respond by calling this tool with the requested data."),
+		InputSchema: claudeInputSchema(schema),
+	}
+}
+
+// claudeInputSchema maps an object JSON Schema onto a tool input schema
+func claudeInputSchema(schema map[string]interface{}) anthropic.ToolInputSchemaParam {
+	inputSchema := anthropic.ToolInputSchemaParam{ExtraFields: map[string]any{}}
+	for key, value := range schema {
+		switch key {
+		case "type":
+		case "properties":
+			inputSchema.Properties = value
+		case "required":
+			switch required := value.(type) {
+			case []string:
+				inputSchema.Required = required
+			case []interface{}:
+				for _, name := range required {
+					if name, ok := name.(string); ok {
+						inputSchema.Required = append(inputSchema.Required, name)
+					}
+				}
+			}
+		default:
+			inputSchema.ExtraFields[key] = value
+		}
+	}
+	return inputSchema
+}
+
+// claudeToolCalls collects the tool_use blocks of a message, except the forced structured output tool
+func claudeToolCalls(req TextGenerateRequest, blocks []anthropic.ContentBlockUnion) []ToolCall {
+	if req.ResponseFormat.structured() {
+		return nil
 	}
 
+	var calls []ToolCall
+	for _, block := range blocks {
+		if string(block.Type) != "tool_use" {
+			continue
+		}
+		arguments := string(block.Input)
+		if arguments == "" {
+			arguments = "{}"
+		}
+		calls = append(calls, ToolCall{ID: block.ID, Name: block.Name, Arguments: arguments})
+	}
+	return calls
+}
+
+// validateClaudeThinking rejects settings extended thinking does not work with
+func validateClaudeThinking(req TextGenerateRequest, options modelOptions, budget int) error {
+	if budget < 1024 {
+		return fmt.Errorf("thinking budget must be at least 1024 tokens, got %d", budget)
+	}
 	if req.Temperature != nil {
-		messageReq.Temperature = param.NewOpt(float64(*req.Temperature))
+		return errors.New("temperature cannot be set together with extended thinking")
+	}
+	if _, ok := options.int("top_k"); ok {
+		return errors.New("top_k cannot be set together with extended thinking")
+	}
+	if req.ResponseFormat.structured() {
+		return errors.New("Claude does not support structured output together with extended thinking")
+	}
+	// Later turns would have to replay the signed thinking blocks, which the message history does not keep
+	if len(req.Tools) > 0 {
+		return errors.New("Claude does not support tools together with extended thinking")
+	}
+	return nil
+}
+
+// claudeContent joins the text blocks of a message, or returns the forced tool input for structured output
+func claudeContent(req TextGenerateRequest, blocks []anthropic.ContentBlockUnion) (string, error) {
+	format := req.ResponseFormat
+	if !format.structured() {
+		var content string
+		for _, block := range blocks {
+			if string(block.Type) == "text" {
+				content += block.Text
+			}
+		}
+		return content, nil
 	}
 
-	resp, err := c.client.Messages.New(ctx, messageReq)
+	for _, block := range blocks {
+		if string(block.Type) != "tool_use" || block.Name != format.schemaName() {
+			continue
+		}
+		if !claudeWrapsSchema(format) {
+			return string(block.Input), nil
+		}
+		var wrapper struct {
+			Result json.RawMessage `json:"result"`
+		}
+		if err := json.Unmarshal(block.Input, &wrapper); err != nil {
+			return "", &StructuredOutputError{Content: string(block.Input), Err: err}
+		}
+		return string(wrapper.Result), nil
+	}
+	return "", &StructuredOutputError{Err: errors.New("Claude did not return the structured output")}
+}
+
+// GenerateText generates text using Claude's messages API
+func (c *ClaudeClient) GenerateText(ctx context.Context, req TextGenerateRequest) (*TextGenerateResponse, error) {
+	if req.Model == "" {
+		req.Model = "claude-3-5-sonnet-20241022"
+	}
+
+	messageReq, err := c.buildMessageParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := withRetry(ctx, c.retry, func(ctx context.Context) (*anthropic.Message, error) {
+		return c.client.Messages.New(ctx, messageReq)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("Claude message generation failed: %w", err)
 	}
@@ -99,20 +357,106 @@ func (c *ClaudeClient) GenerateText(ctx context.Context, req TextGenerateRequest
 		return nil, errors.New("no response from Claude")
 	}
 
-	var content string
-	for _, block := range resp.Content {
-		if string(block.Type) == "text" {
-			content += block.Text
-		}
+	content, err := claudeContent(req, resp.Content)
+	if err != nil {
+		return nil, err
 	}
 
-	return &TextGenerateResponse{
+	result := &TextGenerateResponse{
 		Content:      content,
+		ToolCalls:    claudeToolCalls(req, resp.Content),
 		PromptTokens: int(resp.Usage.InputTokens),
 		OutputTokens: int(resp.Usage.OutputTokens),
 		TotalTokens:  int(resp.Usage.InputTokens + resp.Usage.OutputTokens),
 		Model:        string(resp.Model),
-	}, nil
+	}
+	if err := checkStructuredOutput(req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamText streams text using Claude's messages API, calling onDelta for every text delta
+func (c *ClaudeClient) StreamText(ctx context.Context, req TextGenerateRequest, onDelta func(delta string)) (*TextGenerateResponse, error) {
+	if req.Model == "" {
+		req.Model = "claude-3-5-sonnet-20241022"
+	}
+
+	messageReq, err := c.buildMessageParams(req)
+	if err != nil {
+		return nil, err
+	}
+
+	structured := req.ResponseFormat.structured()
+	wrapped := structured && claudeWrapsSchema(req.ResponseFormat)
+
+	message, err := withRetry(ctx, c.retry, func(ctx context.Context) (anthropic.Message, error) {
+		stream := c.client.Messages.NewStreaming(ctx, messageReq)
+		defer stream.Close()
+
+		message := anthropic.Message{}
+		emitted := false
+		for stream.Next() {
+			event := stream.Current()
+			if err := message.Accumulate(event); err != nil {
+				return message, &permanentError{fmt.Errorf("Claude message stream failed: %w", err)}
+			}
+
+			if delta, ok := event.AsAny().(anthropic.ContentBlockDeltaEvent); ok {
+				switch d := delta.Delta.AsAny().(type) {
+				case anthropic.TextDelta:
+					if d.Text != "" && !structured {
+						emitted = true
+						onDelta(d.Text)
+					}
+				case anthropic.InputJSONDelta:
+					// The forced tool input is the answer, unless it is wrapped and only sent once complete
+					if d.PartialJSON != "" && structured && !wrapped {
+						emitted = true
+						onDelta(d.PartialJSON)
+					}
+				}
+			}
+		}
+		if err := stream.Err(); err != nil {
+			err = fmt.Errorf("Claude message stream failed: %w", err)
+			// Retrying after output was emitted would duplicate it
+			if emitted {
+				return message, &permanentError{err}
+			}
+			return message, err
+		}
+		return message, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := claudeContent(req, message.Content)
+	if err != nil {
+		return nil, err
+	}
+	if wrapped {
+		onDelta(content)
+	}
+
+	model := string(message.Model)
+	if model == "" {
+		model = req.Model
+	}
+
+	result := &TextGenerateResponse{
+		Content:      content,
+		ToolCalls:    claudeToolCalls(req, message.Content),
+		PromptTokens: int(message.Usage.InputTokens),
+		OutputTokens: int(message.Usage.OutputTokens),
+		TotalTokens:  int(message.Usage.InputTokens + message.Usage.OutputTokens),
+		Model:        model,
+	}
+	if err := checkStructuredOutput(req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // GenerateImage is not supported by Claude
@@ -125,6 +469,16 @@ func (c *ClaudeClient) GenerateAudio(ctx context.Context, req AudioGenerateReque
 	return nil, errors.New("audio generation is not supported by Claude")
 }
 
+// Transcribe is not supported by Claude
+func (c *ClaudeClient) Transcribe(ctx context.Context, req TranscribeRequest) (*TranscribeResponse, error) {
+	return nil, errors.New("transcription is not supported by Claude")
+}
+
+// Embed is not supported by Claude
+func (c *ClaudeClient) Embed(ctx context.Context, req EmbedRequest) (*EmbedResponse, error) {
+	return nil, errors.New("embeddings are not supported by Claude")
+}
+
 // GenerateVideo is not supported by Claude
 func (c *ClaudeClient) GenerateVideo(ctx context.Context, req VideoGenerateRequest) (*VideoGenerateResponse, error) {
 	return nil, errors.New("video generation is not supported by Claude")
@@ -155,5 +509,5 @@ func (c *ClaudeClient) ListModels(ctx context.Context) ([]Model, error) {
 		return nil, fmt.Errorf("failed to list Claude models: %w", err)
 	}
 
-	return models, nil
+	return annotateModels(c.config, models), nil
 }
@@ -2,6 +2,8 @@ package ai
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -10,6 +12,8 @@ import (
 	"mime"
 	"net/http"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -20,6 +24,7 @@ import (
 type GeminiClient struct {
 	client *genai.Client
 	config database.ModelProvider
+	retry  RetryPolicy
 }
 
 // NewGeminiClient creates a new Google Gemini client
@@ -29,7 +34,8 @@ func NewGeminiClient(config database.ModelProvider) (*GeminiClient, error) {
 	}
 
 	clientConfig := &genai.ClientConfig{
-		APIKey: config.APIKey,
+		APIKey:     config.APIKey,
+		HTTPClient: newHTTPClient(),
 	}
 	if config.BaseURL != "" {
 		clientConfig.HTTPOptions = genai.HTTPOptions{
@@ -46,6 +52,7 @@ func NewGeminiClient(config database.ModelProvider) (*GeminiClient, error) {
 	return &GeminiClient{
 		client: client,
 		config: config,
+		retry:  retryPolicyFor(config),
 	}, nil
 }
 
@@ -107,10 +114,17 @@ func (c *GeminiClient) processInputs(images, videos, audios, documents []string)
 	return parts, nil
 }
 
-// GenerateText generates text using Gemini's generate content API
-func (c *GeminiClient) GenerateText(ctx context.Context, req TextGenerateRequest) (*TextGenerateResponse, error) {
-	if req.Model == "" {
-		req.Model = "gemini-2.0-flash-exp"
+// buildTextRequest maps a TextGenerateRequest onto Gemini contents and generation config
+func (c *GeminiClient) buildTextRequest(req TextGenerateRequest) ([]*genai.Content, *genai.GenerateContentConfig, error) {
+	if err := req.ResponseFormat.validate(); err != nil {
+		return nil, nil, err
+	}
+	if err := req.validateTools(); err != nil {
+		return nil, nil, err
+	}
+	options, err := resolveOptions(database.ProviderGemini, req.Model, req.Options)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Configure generation options
@@ -122,19 +136,158 @@ func (c *GeminiClient) GenerateText(ctx context.Context, req TextGenerateRequest
 	if req.MaxTokens != nil {
 		genConfig.MaxOutputTokens = int32(*req.MaxTokens)
 	}
+	applyGeminiOptions(genConfig, options)
+	if format := req.ResponseFormat; format.structured() {
+		genConfig.ResponseMIMEType = "application/json"
+		if len(format.Schema) > 0 {
+			genConfig.ResponseJsonSchema = format.Schema
+		}
+	}
+
+	if len(req.Tools) > 0 {
+		declarations := make([]*genai.FunctionDeclaration, 0, len(req.Tools))
+		for _, tool := range req.Tools {
+			declarations = append(declarations, &genai.FunctionDeclaration{
+				Name:                 tool.Name,
+				Description:          tool.Description,
+				ParametersJsonSchema: tool.toolParameters(),
+			})
+		}
+		genConfig.Tools = []*genai.Tool{{FunctionDeclarations: declarations}}
+
+		switch req.ToolChoice {
+		case "", ToolChoiceAuto:
+		case ToolChoiceNone:
+			genConfig.ToolConfig = &genai.ToolConfig{FunctionCallingConfig: &genai.FunctionCallingConfig{
+				Mode: genai.FunctionCallingConfigModeNone,
+			}}
+		case ToolChoiceRequired:
+			genConfig.ToolConfig = &genai.ToolConfig{FunctionCallingConfig: &genai.FunctionCallingConfig{
+				Mode: genai.FunctionCallingConfigModeAny,
+			}}
+		default:
+			genConfig.ToolConfig = &genai.ToolConfig{FunctionCallingConfig: &genai.FunctionCallingConfig{
+				Mode:                 genai.FunctionCallingConfigModeAny,
+				AllowedFunctionNames: []string{req.ToolChoice},
+			}}
+		}
+	}
 
-	parts := []*genai.Part{{Text: req.Prompt}}
+	if req.System != "" {
+		genConfig.SystemInstruction = genai.NewContentFromText(req.System, genai.RoleUser)
+	}
 
-	multimodalParts, err := c.processInputs(req.Images, req.Videos, req.Audios, req.Documents)
+	conversation, err := req.conversation()
 	if err != nil {
-		return nil, fmt.Errorf("failed to process multimodal inputs: %w", err)
+		return nil, nil, err
 	}
-	parts = append(parts, multimodalParts...)
 
-	contents := []*genai.Content{{Parts: parts}}
+	contents := make([]*genai.Content, 0, len(conversation))
+	for _, msg := range conversation {
+		if msg.Role == RoleTool {
+			contents = append(contents, &genai.Content{Role: string(genai.RoleUser), Parts: geminiToolResults(msg.ToolResults)})
+			continue
+		}
+
+		multimodalParts, err := c.processInputs(msg.Images, msg.Videos, msg.Audios, msg.Documents)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to process multimodal inputs: %w", err)
+		}
+
+		// Gemini rejects empty text parts, a turn may consist of attachments only
+		var parts []*genai.Part
+		if msg.Content != "" || (len(multimodalParts) == 0 && len(msg.ToolCalls) == 0) {
+			parts = append(parts, &genai.Part{Text: msg.Content})
+		}
+		parts = append(parts, multimodalParts...)
+		for _, call := range msg.ToolCalls {
+			part, err := geminiFunctionCall(call)
+			if err != nil {
+				return nil, nil, err
+			}
+			parts = append(parts, part)
+		}
+
+		role := genai.RoleUser
+		if msg.Role == RoleAssistant {
+			role = genai.RoleModel
+		}
+		contents = append(contents, &genai.Content{Role: string(role), Parts: parts})
+	}
+
+	return contents, genConfig, nil
+}
+
+// geminiFunctionCall maps an earlier tool call back onto a function call part, with its thought signature
+func geminiFunctionCall(call ToolCall) (*genai.Part, error) {
+	var args map[string]any
+	if call.Arguments != "" {
+		if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil {
+			return nil, fmt.Errorf("invalid arguments for tool call %s: %w", call.ID, err)
+		}
+	}
+
+	part := &genai.Part{FunctionCall: &genai.FunctionCall{ID: call.ID, Name: call.Name, Args: args}}
+	if call.Signature != "" {
+		signature, err := base64.StdEncoding.DecodeString(call.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature for tool call %s: %w", call.ID, err)
+		}
+		part.ThoughtSignature = signature
+	}
+	return part, nil
+}
+
+// geminiToolResults maps tool results onto function response parts, Gemini expects an object response
+func geminiToolResults(results []ToolResult) []*genai.Part {
+	parts := make([]*genai.Part, 0, len(results))
+	for _, result := range results {
+		response := map[string]any{"output": result.Content}
+		if result.IsError {
+			response = map[string]any{"error": result.Content}
+		}
+		parts = append(parts, &genai.Part{FunctionResponse: &genai.FunctionResponse{
+			ID:       result.CallID,
+			Name:     result.Name,
+			Response: response,
+		}})
+	}
+	return parts
+}
+
+// geminiToolCall maps a function call part onto a ToolCall. Gemini only returns IDs on some
+// backends, index keeps generated IDs unique within a response.
+func geminiToolCall(part *genai.Part, index int) ToolCall {
+	call := ToolCall{ID: part.FunctionCall.ID, Name: part.FunctionCall.Name, Arguments: "{}"}
+	if call.ID == "" {
+		call.ID = fmt.Sprintf("call_%d", index)
+	}
+	if len(part.FunctionCall.Args) > 0 {
+		if args, err := json.Marshal(part.FunctionCall.Args); err == nil {
+			call.Arguments = string(args)
+		}
+	}
+	if len(part.ThoughtSignature) > 0 {
+		call.Signature = base64.StdEncoding.EncodeToString(part.ThoughtSignature)
+	}
+	return call
+}
+
+// GenerateText generates text using Gemini's generate content API
+func (c *GeminiClient) GenerateText(ctx context.Context, req TextGenerateRequest) (*TextGenerateResponse, error) {
+	if req.Model == "" {
+		req.Model = "gemini-2.0-flash-exp"
+	}
+
+	contents, genConfig, err := c.buildTextRequest(req)
+	if err != nil {
+		return nil, err
+	}
 
 	// Call the API
-	resp, err := c.client.Models.GenerateContent(ctx, req.Model, contents, genConfig)
+	resp, err := withRetry(ctx, c.retry, func(ctx context.Context) (*genai.GenerateContentResponse, error) {
+		return c.client.Models.GenerateContent(ctx, req.Model, contents, genConfig)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("Gemini content generation failed: %w", err)
 	}
@@ -147,10 +300,15 @@ func (c *GeminiClient) GenerateText(ctx context.Context, req TextGenerateRequest
 		totalTokens = int(resp.UsageMetadata.TotalTokenCount)
 	}
 
-	// Extract text content
+	// Extract text content and function calls
 	var content string
+	var calls []ToolCall
 	if len(resp.Candidates) > 0 {
 		for _, part := range resp.Candidates[0].Content.Parts {
+			if part.FunctionCall != nil {
+				calls = append(calls, geminiToolCall(part, len(calls)))
+				continue
+			}
 			if part.Text != "" {
 				content += part.Text
 			}
@@ -159,22 +317,111 @@ func (c *GeminiClient) GenerateText(ctx context.Context, req TextGenerateRequest
 		return nil, errors.New("no response content from Gemini")
 	}
 
-	return &TextGenerateResponse{
+	result := &TextGenerateResponse{
 		Content:      content,
+		ToolCalls:    calls,
 		PromptTokens: promptTokens,
 		OutputTokens: outputTokens,
 		TotalTokens:  totalTokens,
 		Model:        req.Model,
-	}, nil
+	}
+	if err := checkStructuredOutput(req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamText streams text using Gemini's generate content API, calling onDelta for every text chunk
+func (c *GeminiClient) StreamText(ctx context.Context, req TextGenerateRequest, onDelta func(delta string)) (*TextGenerateResponse, error) {
+	if req.Model == "" {
+		req.Model = "gemini-2.0-flash-exp"
+	}
+
+	contents, genConfig, err := c.buildTextRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := withRetry(ctx, c.retry, func(ctx context.Context) (*TextGenerateResponse, error) {
+		result := &TextGenerateResponse{Model: req.Model}
+		var content strings.Builder
+		var calls []ToolCall
+		for resp, err := range c.client.Models.GenerateContentStream(ctx, req.Model, contents, genConfig) {
+			if err != nil {
+				err = fmt.Errorf("Gemini content stream failed: %w", err)
+				// Retrying after output was emitted would duplicate it
+				if content.Len() > 0 || len(calls) > 0 {
+					return nil, &permanentError{err}
+				}
+				return nil, err
+			}
+
+			// Every chunk carries cumulative usage, so the last one wins
+			if resp.UsageMetadata != nil {
+				result.PromptTokens = int(resp.UsageMetadata.PromptTokenCount)
+				result.OutputTokens = int(resp.UsageMetadata.CandidatesTokenCount)
+				result.TotalTokens = int(resp.UsageMetadata.TotalTokenCount)
+			}
+
+			if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+				continue
+			}
+			for _, part := range resp.Candidates[0].Content.Parts {
+				// Function calls arrive whole, never split across chunks
+				if part.FunctionCall != nil {
+					calls = append(calls, geminiToolCall(part, len(calls)))
+					continue
+				}
+				if part.Text != "" {
+					content.WriteString(part.Text)
+					onDelta(part.Text)
+				}
+			}
+		}
+
+		result.Content = content.String()
+		result.ToolCalls = calls
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStructuredOutput(req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // GenerateImage generates an image using Gemini's image generation capabilities
 func (c *GeminiClient) GenerateImage(ctx context.Context, req ImageGenerateRequest) (*ImageGenerateResponse, error) {
 	if req.Model == "" {
 		req.Model = "imagen-3.0-generate-001"
+		if len(req.Images) > 0 {
+			// Imagen cannot take input images, editing needs a Gemini image model
+			req.Model = "gemini-2.5-flash-image"
+		}
+	}
+	if req.Mask != "" && len(req.Images) == 0 {
+		return nil, errors.New("a mask needs an input image to edit")
+	}
+
+	options, err := resolveOptions(database.ProviderGemini, req.Model, req.Options)
+	if err != nil {
+		return nil, err
+	}
+	if isImagenModel(req.Model) {
+		return c.generateImagen(ctx, req, options)
+	}
+	if req.N > 1 {
+		return nil, fmt.Errorf("%s generates one image per request", req.Model)
 	}
 
-	parts := []*genai.Part{{Text: req.Prompt}}
+	prompt := req.Prompt
+	if req.NegativePrompt != "" {
+		prompt += "\n\nDo not include: " + req.NegativePrompt
+	}
+	parts := []*genai.Part{{Text: prompt}}
 
 	multimodalParts, err := c.processInputs(req.Images, req.Videos, req.Audios, nil)
 	if err != nil {
@@ -182,10 +429,32 @@ func (c *GeminiClient) GenerateImage(ctx context.Context, req ImageGenerateReque
 	}
 	parts = append(parts, multimodalParts...)
 
+	// Gemini has no mask parameter, the mask is sent as another image and explained in the prompt
+	if req.Mask != "" {
+		maskParts, err := c.processInputs([]string{req.Mask}, nil, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process mask: %w", err)
+		}
+		parts = append(parts, &genai.Part{Text: "The next image is a mask for the first image. Only change the area " +
+			"where the mask is transparent and keep every other pixel of the first image unchanged."})
+		parts = append(parts, maskParts...)
+	}
+
 	contents := []*genai.Content{{Parts: parts}}
 
-	// Use GenerateContent for image generation
-	resp, err := c.client.Models.GenerateContent(ctx, req.Model, contents, nil)
+	genConfig := &genai.GenerateContentConfig{}
+	if req.AspectRatio != "" {
+		genConfig.ImageConfig = &genai.ImageConfig{AspectRatio: req.AspectRatio}
+	}
+	if req.Seed != nil {
+		seed := int32(*req.Seed)
+		genConfig.Seed = &seed
+	}
+
+	// Gemini native image models generate through GenerateContent
+	resp, err := withCreateRetry(ctx, c.retry, func(ctx context.Context) (*genai.GenerateContentResponse, error) {
+		return c.client.Models.GenerateContent(ctx, req.Model, contents, genConfig)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("Gemini image generation failed: %w", err)
 	}
@@ -198,8 +467,8 @@ func (c *GeminiClient) GenerateImage(ctx context.Context, req ImageGenerateReque
 	for _, part := range resp.Candidates[0].Content.Parts {
 		if part.InlineData != nil {
 			return &ImageGenerateResponse{
-				Data:  part.InlineData.Data,
-				Model: req.Model,
+				Images: []GeneratedImage{{Data: part.InlineData.Data}},
+				Model:  req.Model,
 			}, nil
 		}
 	}
@@ -207,22 +476,280 @@ func (c *GeminiClient) GenerateImage(ctx context.Context, req ImageGenerateReque
 	return nil, errors.New("no image data in Gemini response")
 }
 
+// applyGeminiOptions sets the sampling and thinking options of a GenerateContent call
+func applyGeminiOptions(genConfig *genai.GenerateContentConfig, options modelOptions) {
+	if topP, ok := options.float("top_p"); ok {
+		value := float32(topP)
+		genConfig.TopP = &value
+	}
+	if topK, ok := options.int("top_k"); ok {
+		value := float32(topK)
+		genConfig.TopK = &value
+	}
+	if budget, ok := options.int("thinking_budget"); ok {
+		value := int32(budget)
+		genConfig.ThinkingConfig = &genai.ThinkingConfig{ThinkingBudget: &value}
+	}
+}
+
+// isImagenModel reports whether model is an Imagen model, which only supports the GenerateImages API
+func isImagenModel(model string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(model, "models/"), "imagen-")
+}
+
+// generateImagen generates images from text with an Imagen model
+func (c *GeminiClient) generateImagen(ctx context.Context, req ImageGenerateRequest, options modelOptions) (*ImageGenerateResponse, error) {
+	if len(req.Images) > 0 || len(req.Videos) > 0 || len(req.Audios) > 0 {
+		return nil, fmt.Errorf("%s only generates from text, use a Gemini image model to edit images", req.Model)
+	}
+
+	prompt := req.Prompt
+	config := &genai.GenerateImagesConfig{
+		NumberOfImages: 1,
+		AspectRatio:    req.AspectRatio,
+	}
+	config.ImageSize, _ = options.string("image_size")
+	if req.NegativePrompt != "" {
+		// Only Vertex AI takes a negative prompt, the Gemini API gets it as part of the prompt
+		if c.client.ClientConfig().Backend == genai.BackendVertexAI {
+			config.NegativePrompt = req.NegativePrompt
+		} else {
+			prompt += "\n\nDo not include: " + req.NegativePrompt
+		}
+	}
+	if req.N > 0 {
+		config.NumberOfImages = int32(req.N)
+	}
+	if req.PersonGeneration != "" {
+		personGeneration, err := geminiPersonGeneration(req.PersonGeneration)
+		if err != nil {
+			return nil, err
+		}
+		config.PersonGeneration = personGeneration
+	}
+	if req.Seed != nil {
+		seed := int32(*req.Seed)
+		config.Seed = &seed
+	}
+
+	resp, err := withCreateRetry(ctx, c.retry, func(ctx context.Context) (*genai.GenerateImagesResponse, error) {
+		return c.client.Models.GenerateImages(ctx, req.Model, prompt, config)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Imagen image generation failed: %w", err)
+	}
+
+	// Filtered candidates come back without data, the others are still usable
+	var images []GeneratedImage
+	filtered := ""
+	for _, generated := range resp.GeneratedImages {
+		if generated == nil {
+			continue
+		}
+		if generated.Image == nil || len(generated.Image.ImageBytes) == 0 {
+			if generated.RAIFilteredReason != "" {
+				filtered = generated.RAIFilteredReason
+			}
+			continue
+		}
+		images = append(images, GeneratedImage{
+			Data:          generated.Image.ImageBytes,
+			RevisedPrompt: generated.EnhancedPrompt,
+		})
+	}
+
+	if len(images) == 0 {
+		if filtered != "" {
+			return nil, fmt.Errorf("Imagen filtered the image: %s", filtered)
+		}
+		return nil, errors.New("no image generated from Imagen")
+	}
+	return &ImageGenerateResponse{
+		Images: images,
+		Model:  req.Model,
+	}, nil
+}
+
+// geminiPersonGeneration maps a person generation setting onto the Imagen enum, case-insensitively
+func geminiPersonGeneration(value string) (genai.PersonGeneration, error) {
+	switch setting := genai.PersonGeneration(strings.ToUpper(value)); setting {
+	case genai.PersonGenerationDontAllow, genai.PersonGenerationAllowAdult, genai.PersonGenerationAllowAll:
+		return setting, nil
+	}
+	return "", fmt.Errorf("unknown person generation %q, use dont_allow, allow_adult or allow_all", value)
+}
+
 // GenerateAudio is not fully supported by Gemini library in the same way yet
 func (c *GeminiClient) GenerateAudio(ctx context.Context, req AudioGenerateRequest) (*AudioGenerateResponse, error) {
 	return nil, errors.New("audio generation is not fully supported by Gemini yet")
 }
 
+// geminiTranscriptSchema is the structured answer requested for transcription
+var geminiTranscriptSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"language": map[string]any{"type": "string", "description": "ISO-639-1 code of the spoken language"},
+		"duration": map[string]any{"type": "number", "description": "Length of the media in seconds"},
+		"segments": map[string]any{
+			"type": "array",
+			"items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"start": map[string]any{"type": "number", "description": "Start in seconds"},
+					"end":   map[string]any{"type": "number", "description": "End in seconds"},
+					"text":  map[string]any{"type": "string"},
+				},
+				"required": []string{"start", "end", "text"},
+			},
+		},
+	},
+	"required": []string{"segments"},
+}
+
+// Transcribe transcribes audio or video through Gemini's audio understanding, asking for timed segments as JSON
+func (c *GeminiClient) Transcribe(ctx context.Context, req TranscribeRequest) (*TranscribeResponse, error) {
+	if req.Model == "" {
+		req.Model = "gemini-2.5-flash"
+	}
+	options, err := resolveOptions(database.ProviderGemini, req.Model, req.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	var audios, videos []string
+	if strings.HasPrefix(mime.TypeByExtension(filepath.Ext(ContentName(req.Media))), "video/") {
+		videos = []string{req.Media}
+	} else {
+		audios = []string{req.Media}
+	}
+	mediaParts, err := c.processInputs(nil, videos, audios, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process media: %w", err)
+	}
+
+	instruction := "Transcribe the speech in this recording verbatim. Split it into segments of one sentence or phrase " +
+		"with start and end times in seconds. Do not describe sounds or music."
+	if req.Language != "" {
+		instruction += " The spoken language is " + req.Language + "."
+	}
+	if req.Prompt != "" {
+		instruction += " Context: " + req.Prompt
+	}
+
+	contents := []*genai.Content{{Role: string(genai.RoleUser), Parts: append([]*genai.Part{{Text: instruction}}, mediaParts...)}}
+	genConfig := &genai.GenerateContentConfig{
+		ResponseMIMEType:   "application/json",
+		ResponseJsonSchema: geminiTranscriptSchema,
+	}
+	applyGeminiOptions(genConfig, options)
+
+	resp, err := withRetry(ctx, c.retry, func(ctx context.Context) (*genai.GenerateContentResponse, error) {
+		return c.client.Models.GenerateContent(ctx, req.Model, contents, genConfig)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Gemini transcription failed: %w", err)
+	}
+
+	var transcript struct {
+		Language string              `json:"language"`
+		Duration float64             `json:"duration"`
+		Segments []TranscriptSegment `json:"segments"`
+	}
+	if err := json.Unmarshal([]byte(extractJSON(resp.Text())), &transcript); err != nil {
+		return nil, fmt.Errorf("failed to decode Gemini transcript: %w", err)
+	}
+
+	result := &TranscribeResponse{
+		Language: transcript.Language,
+		Duration: transcript.Duration,
+		Model:    req.Model,
+	}
+	var texts []string
+	for _, segment := range transcript.Segments {
+		segment.Text = strings.TrimSpace(segment.Text)
+		if segment.Text == "" {
+			continue
+		}
+		result.Segments = append(result.Segments, segment)
+		texts = append(texts, segment.Text)
+	}
+	result.Text = strings.Join(texts, " ")
+	if n := len(result.Segments); n > 0 && result.Duration < result.Segments[n-1].End {
+		result.Duration = result.Segments[n-1].End
+	}
+	if resp.UsageMetadata != nil {
+		result.PromptTokens = int(resp.UsageMetadata.PromptTokenCount)
+		result.OutputTokens = int(resp.UsageMetadata.CandidatesTokenCount)
+	}
+	return result, nil
+}
+
+// Embed embeds texts using Gemini's embedding models
+func (c *GeminiClient) Embed(ctx context.Context, req EmbedRequest) (*EmbedResponse, error) {
+	if req.Model == "" {
+		req.Model = "gemini-embedding-001"
+	}
+
+	contents := make([]*genai.Content, 0, len(req.Inputs))
+	for _, input := range req.Inputs {
+		contents = append(contents, genai.NewContentFromText(input, genai.RoleUser))
+	}
+
+	embedConfig := &genai.EmbedContentConfig{TaskType: "RETRIEVAL_DOCUMENT"}
+	if req.Task == EmbedTaskQuery {
+		embedConfig.TaskType = "RETRIEVAL_QUERY"
+	}
+	if req.Dimensions > 0 {
+		dimensions := int32(req.Dimensions)
+		embedConfig.OutputDimensionality = &dimensions
+	}
+
+	resp, err := withRetry(ctx, c.retry, func(ctx context.Context) (*genai.EmbedContentResponse, error) {
+		return c.client.Models.EmbedContent(ctx, req.Model, contents, embedConfig)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Gemini embeddings failed: %w", err)
+	}
+	if len(resp.Embeddings) != len(req.Inputs) {
+		return nil, fmt.Errorf("Gemini returned %d embeddings for %d inputs", len(resp.Embeddings), len(req.Inputs))
+	}
+
+	result := &EmbedResponse{Model: req.Model}
+	for _, embedding := range resp.Embeddings {
+		var values []float32
+		if embedding != nil {
+			values = embedding.Values
+		}
+		result.Embeddings = append(result.Embeddings, values)
+	}
+	return result, nil
+}
+
 // GenerateVideo generates a video using Gemini's video generation capabilities
 func (c *GeminiClient) GenerateVideo(ctx context.Context, req VideoGenerateRequest) (*VideoGenerateResponse, error) {
 	if req.Model == "" {
 		req.Model = "veo-3.1-generate-preview"
 	}
 
+	operationName, err := c.StartVideoJob(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.WaitVideoJob(ctx, operationName, req)
+}
+
+// StartVideoJob starts a Veo generation and returns the long-running operation name
+func (c *GeminiClient) StartVideoJob(ctx context.Context, req VideoGenerateRequest) (string, error) {
+	if req.Model == "" {
+		req.Model = "veo-3.1-generate-preview"
+	}
+
 	var referenceImages []*genai.VideoGenerationReferenceImage
 	for _, imagePath := range req.Images {
 		data, err := LoadContent(imagePath)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
 		// Detect MIME type
@@ -238,53 +765,216 @@ func (c *GeminiClient) GenerateVideo(ctx context.Context, req VideoGenerateReque
 		referenceImages = append(referenceImages, refImg)
 	}
 
+	if _, err := resolveOptions(database.ProviderGemini, req.Model, req.Options); err != nil {
+		return "", err
+	}
+	config, err := c.veoConfig(req)
+	if err != nil {
+		return "", err
+	}
+	config.ReferenceImages = referenceImages
+
+	operation, err := withCreateRetry(ctx, c.retry, func(ctx context.Context) (*genai.GenerateVideosOperation, error) {
+		return c.client.Models.GenerateVideos(ctx, req.Model, req.Prompt, nil, config)
+	})
+	if err != nil {
+		return "", fmt.Errorf("Gemini video generation initialization failed: %w", err)
+	}
+	if operation.Name == "" {
+		return "", errors.New("Gemini video generation did not return an operation name")
+	}
+
+	return operation.Name, nil
+}
+
+// veoLimits describes the settings a family of Veo models accepts
+type veoLimits struct {
+	durations   []int
+	resolutions []string
+	maxVideos   int
+	audio       bool // Whether the model generates a soundtrack
+}
+
+// veoLimitsFor returns the limits of a Veo model, everything newer than Veo 2 follows Veo 3
+func veoLimitsFor(model string) veoLimits {
+	if strings.HasPrefix(strings.TrimPrefix(model, "models/"), "veo-2") {
+		return veoLimits{durations: []int{5, 6, 7, 8}, resolutions: []string{"720p"}, maxVideos: 2}
+	}
+	return veoLimits{durations: []int{4, 6, 8}, resolutions: []string{"720p", "1080p"}, maxVideos: 1, audio: true}
+}
+
+// veoConfig maps the request settings onto a Veo generation config, rejecting what the model cannot do
+func (c *GeminiClient) veoConfig(req VideoGenerateRequest) (*genai.GenerateVideosConfig, error) {
+	limits := veoLimitsFor(req.Model)
 	config := &genai.GenerateVideosConfig{
-		ReferenceImages: referenceImages,
+		NegativePrompt: req.NegativePrompt,
+	}
+
+	duration := 0
+	if req.Duration != "" {
+		var err error
+		duration, err = strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(req.Duration), "s"))
+		if err != nil || !slices.Contains(limits.durations, duration) {
+			return nil, fmt.Errorf("%s cannot generate %q long videos, use one of %v seconds", req.Model, req.Duration, limits.durations)
+		}
+		seconds := int32(duration)
+		config.DurationSeconds = &seconds
 	}
 
-	operation, err := c.client.Models.GenerateVideos(ctx, req.Model, req.Prompt, nil, config)
+	resolution, aspectRatio, err := veoResolution(req.Resolution)
 	if err != nil {
-		return nil, fmt.Errorf("Gemini video generation initialization failed: %w", err)
+		return nil, err
+	}
+	if resolution != "" {
+		if !slices.Contains(limits.resolutions, resolution) {
+			return nil, fmt.Errorf("%s cannot generate %s videos, use one of %v", req.Model, resolution, limits.resolutions)
+		}
+		if resolution == "1080p" && duration != 0 && duration != 8 {
+			return nil, fmt.Errorf("1080p videos must be 8 seconds long, got %d", duration)
+		}
+		config.Resolution = resolution
+	}
+
+	if req.AspectRatio != "" {
+		if aspectRatio != "" && aspectRatio != req.AspectRatio {
+			return nil, fmt.Errorf("resolution %s does not match aspect ratio %s", req.Resolution, req.AspectRatio)
+		}
+		aspectRatio = req.AspectRatio
+	}
+	if aspectRatio != "" && aspectRatio != "16:9" && aspectRatio != "9:16" {
+		return nil, fmt.Errorf("unsupported aspect ratio %q, use 16:9 or 9:16", aspectRatio)
+	}
+	config.AspectRatio = aspectRatio
+
+	if req.N > limits.maxVideos {
+		return nil, fmt.Errorf("%s cannot generate %d videos per request, the limit is %d", req.Model, req.N, limits.maxVideos)
+	}
+	if req.N > 0 {
+		config.NumberOfVideos = int32(req.N)
+	}
+
+	if req.Seed != nil {
+		seed := int32(*req.Seed)
+		config.Seed = &seed
 	}
 
+	if req.GenerateAudio != nil {
+		switch {
+		case *req.GenerateAudio && !limits.audio:
+			return nil, fmt.Errorf("%s cannot generate audio", req.Model)
+		case c.client.ClientConfig().Backend == genai.BackendVertexAI:
+			config.GenerateAudio = req.GenerateAudio
+		case !*req.GenerateAudio && limits.audio:
+			return nil, fmt.Errorf("%s always generates audio on the Gemini API", req.Model)
+		}
+		// Otherwise the Gemini API already does what was asked and rejects the parameter
+	}
+
+	return config, nil
+}
+
+// veoResolution converts a resolution such as 1080p or 1920x1080 into Veo's resolution name,
+// a pixel size also fixes the aspect ratio
+func veoResolution(value string) (resolution, aspectRatio string, err error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || strings.HasSuffix(value, "p") {
+		return value, "", nil
+	}
+
+	var width, height int
+	if _, err := fmt.Sscanf(value, "%dx%d", &width, &height); err != nil {
+		return "", "", fmt.Errorf("invalid resolution %q, use 720p, 1080p or a size such as 1280x720", value)
+	}
+	aspectRatio = "16:9"
+	if height > width {
+		aspectRatio = "9:16"
+		width, height = height, width
+	}
+	if width*9 != height*16 {
+		return "", "", fmt.Errorf("resolution %q is neither 16:9 nor 9:16", value)
+	}
+	return fmt.Sprintf("%dp", height), aspectRatio, nil
+}
+
+// WaitVideoJob polls a Veo operation until it is done and downloads every generated video.
+// It can be called for an operation started in a previous session.
+func (c *GeminiClient) WaitVideoJob(ctx context.Context, operationName string, req VideoGenerateRequest) (*VideoGenerateResponse, error) {
+	if req.Model == "" {
+		req.Model = "veo-3.1-generate-preview"
+	}
+
+	operation := &genai.GenerateVideosOperation{Name: operationName}
+
 	// Poll the operation status until the video is ready.
 	for !operation.Done {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		case <-time.After(5 * time.Second):
-			operation, err = c.client.Operations.GetVideosOperation(ctx, operation, nil)
+			// A transient failure of a single poll must not throw away the whole job
+			next, err := withRetry(ctx, c.retry, func(ctx context.Context) (*genai.GenerateVideosOperation, error) {
+				return c.client.Operations.GetVideosOperation(ctx, operation, nil)
+			})
 			if err != nil {
 				return nil, fmt.Errorf("failed to poll operation status: %w", err)
 			}
+			operation = next
+
+			status := "in_progress"
+			if operation.Done {
+				status = "completed"
+			}
+			req.reportProgress(status, operationProgress(operation.Metadata))
 		}
 	}
 
-	if operation.Response == nil || len(operation.Response.GeneratedVideos) == 0 {
-		return nil, errors.New("no videos generated in response")
+	if operation.Error != nil {
+		return nil, fmt.Errorf("Gemini %w: %v", ErrVideoJobFailed, operation.Error["message"])
 	}
 
-	// Download the generated video.
-	video := operation.Response.GeneratedVideos[0]
-	if video.Video == nil {
-		return nil, errors.New("generated video file info is missing")
+	if operation.Response == nil || len(operation.Response.GeneratedVideos) == 0 {
+		return nil, fmt.Errorf("%w: no videos generated in response", ErrVideoJobFailed)
 	}
 
-	data, err := c.client.Files.Download(ctx, video.Video, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to download video content: %w", err)
+	// Download the generated videos.
+	videos := make([]GeneratedVideo, 0, len(operation.Response.GeneratedVideos))
+	for _, video := range operation.Response.GeneratedVideos {
+		if video == nil || video.Video == nil {
+			return nil, fmt.Errorf("%w: generated video file info is missing", ErrVideoJobFailed)
+		}
+
+		data, err := withRetry(ctx, c.retry, func(ctx context.Context) ([]byte, error) {
+			return c.client.Files.Download(ctx, video.Video, nil)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to download video content: %w", err)
+		}
+		videos = append(videos, GeneratedVideo{Data: data})
 	}
 
 	return &VideoGenerateResponse{
-		Data:  data,
-		Model: req.Model,
+		Videos: videos,
+		Model:  req.Model,
 	}, nil
 }
 
+// operationProgress extracts a completion percentage from operation metadata when the backend provides one
+func operationProgress(metadata map[string]any) *int {
+	for _, key := range []string{"progressPercent", "progress"} {
+		if value, ok := metadata[key].(float64); ok {
+			percent := int(value)
+			return &percent
+		}
+	}
+	return nil
+}
+
 // ListModels lists available models from Gemini
 func (c *GeminiClient) ListModels(ctx context.Context) ([]Model, error) {
 	// List method returns a Page[Model]
-	page, err := c.client.Models.List(ctx, nil)
+	page, err := withRetry(ctx, c.retry, func(ctx context.Context) (genai.Page[genai.Model], error) {
+		return c.client.Models.List(ctx, nil)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list Gemini models: %w", err)
 	}
@@ -310,5 +1000,5 @@ func (c *GeminiClient) ListModels(ctx context.Context) ([]Model, error) {
 		})
 	}
 
-	return models, nil
+	return annotateModels(c.config, models), nil
 }
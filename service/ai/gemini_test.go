@@ -0,0 +1,82 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	"visionflow/database"
+)
+
+func TestVeoConfig(t *testing.T) {
+	client, err := NewGeminiClient(database.ModelProvider{Type: database.ProviderGemini, APIKey: "test"})
+	if err != nil {
+		t.Fatalf("NewGeminiClient: %v", err)
+	}
+	yes, no := true, false
+
+	tests := []struct {
+		name    string
+		req     VideoGenerateRequest
+		check   func(t *testing.T, duration int32, resolution, aspectRatio string, videos int32)
+		wantErr string
+	}{
+		{
+			name: "defaults leave everything to the provider",
+			req:  VideoGenerateRequest{Model: "veo-3.0-generate-001"},
+			check: func(t *testing.T, duration int32, resolution, aspectRatio string, videos int32) {
+				if duration != 0 || resolution != "" || aspectRatio != "" || videos != 0 {
+					t.Errorf("got %d, %q, %q, %d, want nothing set", duration, resolution, aspectRatio, videos)
+				}
+			},
+		},
+		{
+			name: "pixel size sets resolution and aspect ratio",
+			req:  VideoGenerateRequest{Model: "veo-3.0-generate-001", Duration: "8s", Resolution: "1080x1920"},
+			check: func(t *testing.T, duration int32, resolution, aspectRatio string, videos int32) {
+				if duration != 8 || resolution != "1080p" || aspectRatio != "9:16" {
+					t.Errorf("got %d, %q, %q, want 8, 1080p, 9:16", duration, resolution, aspectRatio)
+				}
+			},
+		},
+		{
+			name: "veo 2 renders several videos",
+			req:  VideoGenerateRequest{Model: "veo-2.0-generate-001", Duration: "5", N: 2},
+			check: func(t *testing.T, duration int32, resolution, aspectRatio string, videos int32) {
+				if duration != 5 || videos != 2 {
+					t.Errorf("got %d seconds and %d videos, want 5 and 2", duration, videos)
+				}
+			},
+		},
+		{name: "unsupported duration", req: VideoGenerateRequest{Model: "veo-3.0-generate-001", Duration: "5"}, wantErr: "cannot generate \"5\" long videos"},
+		{name: "unparsable duration", req: VideoGenerateRequest{Model: "veo-3.0-generate-001", Duration: "long"}, wantErr: "long videos"},
+		{name: "1080p on veo 2", req: VideoGenerateRequest{Model: "veo-2.0-generate-001", Resolution: "1080p"}, wantErr: "cannot generate 1080p videos"},
+		{name: "short 1080p", req: VideoGenerateRequest{Model: "veo-3.0-generate-001", Resolution: "1080p", Duration: "4"}, wantErr: "must be 8 seconds long"},
+		{name: "size that is not 16:9", req: VideoGenerateRequest{Model: "veo-3.0-generate-001", Resolution: "1024x1024"}, wantErr: "neither 16:9 nor 9:16"},
+		{name: "invalid resolution", req: VideoGenerateRequest{Model: "veo-3.0-generate-001", Resolution: "big"}, wantErr: "invalid resolution"},
+		{name: "size against aspect ratio", req: VideoGenerateRequest{Model: "veo-3.0-generate-001", Resolution: "1280x720", AspectRatio: "9:16"}, wantErr: "does not match aspect ratio"},
+		{name: "unsupported aspect ratio", req: VideoGenerateRequest{Model: "veo-3.0-generate-001", AspectRatio: "4:3"}, wantErr: "unsupported aspect ratio"},
+		{name: "too many videos", req: VideoGenerateRequest{Model: "veo-3.0-generate-001", N: 2}, wantErr: "the limit is 1"},
+		{name: "audio on veo 2", req: VideoGenerateRequest{Model: "veo-2.0-generate-001", GenerateAudio: &yes}, wantErr: "cannot generate audio"},
+		{name: "silent veo 3 on the Gemini API", req: VideoGenerateRequest{Model: "veo-3.0-generate-001", GenerateAudio: &no}, wantErr: "always generates audio"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := client.veoConfig(tt.req)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var duration int32
+			if config.DurationSeconds != nil {
+				duration = *config.DurationSeconds
+			}
+			tt.check(t, duration, config.Resolution, config.AspectRatio, config.NumberOfVideos)
+		})
+	}
+}
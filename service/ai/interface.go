@@ -2,12 +2,34 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"visionflow/database"
 )
 
-// TextGenerateRequest defines the parameters for text generation
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// Message is one earlier turn of a conversation, attachments are paths or URLs like in the request
+type Message struct {
+	Role        string       `json:"role"` // user, assistant or tool
+	Content     string       `json:"content"`
+	Images      []string     `json:"images,omitempty"`
+	Videos      []string     `json:"videos,omitempty"`
+	Audios      []string     `json:"audios,omitempty"`
+	Documents   []string     `json:"documents,omitempty"`
+	ToolCalls   []ToolCall   `json:"toolCalls,omitempty"`   // Tools the assistant called in this turn
+	ToolResults []ToolResult `json:"toolResults,omitempty"` // Results of a tool turn
+}
+
+// TextGenerateRequest defines the parameters for text generation.
+// Messages holds the conversation so far; Prompt and the attachments form the new user turn.
 type TextGenerateRequest struct {
+	System      string                 `json:"system,omitempty"` // System instruction, e.g. a persona
+	Messages    []Message              `json:"messages,omitempty"`
 	Prompt      string                 `json:"prompt"`
 	Images      []string               `json:"images,omitempty"`
 	Videos      []string               `json:"videos,omitempty"`
@@ -17,37 +39,83 @@ type TextGenerateRequest struct {
 	Temperature *float64               `json:"temperature,omitempty"`
 	MaxTokens   *int                   `json:"maxTokens,omitempty"`
 	Options     map[string]interface{} `json:"options,omitempty"`
+	// ResponseFormat, if set, asks for JSON output which is validated before it is returned
+	ResponseFormat *ResponseFormat `json:"responseFormat,omitempty"`
+	Tools          []Tool          `json:"tools,omitempty"`
+	ToolChoice     string          `json:"toolChoice,omitempty"` // auto, none, required or a tool name
+}
+
+// conversation returns the history followed by the new user turn built from Prompt and the attachments
+func (req TextGenerateRequest) conversation() ([]Message, error) {
+	messages := make([]Message, 0, len(req.Messages)+1)
+	for i, msg := range req.Messages {
+		switch msg.Role {
+		case RoleUser, RoleAssistant:
+		case RoleTool:
+			if len(msg.ToolResults) == 0 {
+				return nil, fmt.Errorf("tool message %d has no results", i)
+			}
+		default:
+			return nil, fmt.Errorf("message %d has unsupported role %q", i, msg.Role)
+		}
+		messages = append(messages, msg)
+	}
+
+	turn := Message{
+		Role:      RoleUser,
+		Content:   req.Prompt,
+		Images:    req.Images,
+		Videos:    req.Videos,
+		Audios:    req.Audios,
+		Documents: req.Documents,
+	}
+	if turn.Content != "" || len(turn.Images)+len(turn.Videos)+len(turn.Audios)+len(turn.Documents) > 0 || len(messages) == 0 {
+		messages = append(messages, turn)
+	}
+	return messages, nil
 }
 
 // TextGenerateResponse defines the response for text generation
 type TextGenerateResponse struct {
-	Content      string `json:"content"`
-	PromptTokens int    `json:"promptTokens"`
-	OutputTokens int    `json:"outputTokens"`
-	TotalTokens  int    `json:"totalTokens"`
-	Model        string `json:"model"`
+	Content      string     `json:"content"`
+	ToolCalls    []ToolCall `json:"toolCalls,omitempty"` // Tools the model wants to run before it answers
+	PromptTokens int        `json:"promptTokens"`
+	OutputTokens int        `json:"outputTokens"`
+	TotalTokens  int        `json:"totalTokens"`
+	Model        string     `json:"model"`
 }
 
 // ImageGenerateRequest defines the parameters for image generation
 type ImageGenerateRequest struct {
-	Prompt  string                 `json:"prompt"`
-	Images  []string               `json:"images,omitempty"`
-	Videos  []string               `json:"videos,omitempty"`
-	Audios  []string               `json:"audios,omitempty"`
-	Model   string                 `json:"model"`
-	Size    string                 `json:"size,omitempty"`
-	Quality string                 `json:"quality,omitempty"`
-	Style   string                 `json:"style,omitempty"`
-	Options map[string]interface{} `json:"options,omitempty"`
+	Prompt           string                 `json:"prompt"`
+	Images           []string               `json:"images,omitempty"` // Input images, the request becomes an edit of Images[0]
+	Videos           []string               `json:"videos,omitempty"`
+	Audios           []string               `json:"audios,omitempty"`
+	Mask             string                 `json:"mask,omitempty"` // PNG sized like Images[0], fully transparent pixels mark the region to edit
+	Model            string                 `json:"model"`
+	Size             string                 `json:"size,omitempty"`
+	Quality          string                 `json:"quality,omitempty"`
+	Style            string                 `json:"style,omitempty"`
+	N                int                    `json:"n,omitempty"`                // Number of candidate images, defaults to 1
+	AspectRatio      string                 `json:"aspectRatio,omitempty"`      // e.g. 1:1, 3:4 or 16:9, for models sized by ratio
+	NegativePrompt   string                 `json:"negativePrompt,omitempty"`   // What the image must not contain
+	PersonGeneration string                 `json:"personGeneration,omitempty"` // dont_allow, allow_adult or allow_all
+	Seed             *int                   `json:"seed,omitempty"`
+	Options          map[string]interface{} `json:"options,omitempty"`
 }
 
 // ImageGenerateResponse defines the response for image generation
 type ImageGenerateResponse struct {
+	Images []GeneratedImage `json:"images"` // One entry per requested image, at least one
+	Model  string           `json:"model"`
+}
+
+// GeneratedImage is a single image of an ImageGenerateResponse, given as URL, base64 or raw data
+type GeneratedImage struct {
 	URL           string `json:"url,omitempty"`
 	B64JSON       string `json:"b64_json,omitempty"`
 	Data          []byte `json:"data,omitempty"`
 	RevisedPrompt string `json:"revised_prompt,omitempty"`
-	Model         string `json:"model"`
 }
 
 // AudioGenerateRequest defines the parameters for audio generation
@@ -64,50 +132,152 @@ type AudioGenerateRequest struct {
 
 // AudioGenerateResponse defines the response for audio generation
 type AudioGenerateResponse struct {
-	Data  []byte `json:"data,omitempty"` // Raw audio data
-	Model string `json:"model"`
+	Data   []byte `json:"data,omitempty"`   // Raw audio data
+	Format string `json:"format,omitempty"` // File extension of Data, e.g. wav; empty means mp3
+	Model  string `json:"model"`
+}
+
+// TranscribeRequest defines the parameters for speech-to-text
+type TranscribeRequest struct {
+	Media    string                 `json:"media"` // Path or URL of an audio or video file
+	Model    string                 `json:"model"`
+	Language string                 `json:"language,omitempty"` // ISO-639-1 hint, e.g. en
+	Prompt   string                 `json:"prompt,omitempty"`   // Context such as names and terms to spell correctly
+	Options  map[string]interface{} `json:"options,omitempty"`
+}
+
+// TranscriptSegment is a timed piece of a transcript, times are in seconds from the start
+type TranscriptSegment struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// TranscribeResponse defines the response for speech-to-text.
+// Segments are empty when the model does not report timestamps.
+type TranscribeResponse struct {
+	Text         string              `json:"text"`
+	Language     string              `json:"language,omitempty"`
+	Duration     float64             `json:"duration,omitempty"` // Length of the media in seconds, 0 if unknown
+	Segments     []TranscriptSegment `json:"segments,omitempty"`
+	PromptTokens int                 `json:"promptTokens,omitempty"`
+	OutputTokens int                 `json:"outputTokens,omitempty"`
+	Model        string              `json:"model"`
+}
+
+const (
+	EmbedTaskDocument = "document" // Texts stored for retrieval, the default
+	EmbedTaskQuery    = "query"    // A search query compared against documents
+)
+
+// EmbedRequest defines the parameters for text embeddings
+type EmbedRequest struct {
+	Inputs     []string `json:"inputs"`
+	Model      string   `json:"model"`
+	Task       string   `json:"task,omitempty"`       // document or query, some models embed them differently
+	Dimensions int      `json:"dimensions,omitempty"` // 0 keeps the model default
+}
+
+// EmbedResponse holds one vector per input, in input order
+type EmbedResponse struct {
+	Embeddings   [][]float32 `json:"embeddings"`
+	PromptTokens int         `json:"promptTokens,omitempty"`
+	Model        string      `json:"model"`
 }
 
 // VideoGenerateRequest defines the parameters for video generation
 type VideoGenerateRequest struct {
-	Prompt     string                 `json:"prompt"`
-	Images     []string               `json:"images,omitempty"`
-	Videos     []string               `json:"videos,omitempty"`
-	Audios     []string               `json:"audios,omitempty"`
-	Model      string                 `json:"model"`
-	Duration   string                 `json:"duration,omitempty"`
-	Resolution string                 `json:"resolution,omitempty"`
-	Options    map[string]interface{} `json:"options,omitempty"`
+	Prompt         string                 `json:"prompt"`
+	Images         []string               `json:"images,omitempty"`
+	Videos         []string               `json:"videos,omitempty"`
+	Audios         []string               `json:"audios,omitempty"`
+	Model          string                 `json:"model"`
+	Duration       string                 `json:"duration,omitempty"`    // Seconds, e.g. 8 or 8s
+	Resolution     string                 `json:"resolution,omitempty"`  // A size such as 1280x720, or 720p / 1080p for Veo
+	AspectRatio    string                 `json:"aspectRatio,omitempty"` // 16:9 or 9:16
+	NegativePrompt string                 `json:"negativePrompt,omitempty"`
+	Seed           *int                   `json:"seed,omitempty"`
+	GenerateAudio  *bool                  `json:"generateAudio,omitempty"` // nil keeps the model default
+	N              int                    `json:"n,omitempty"`             // Number of videos, defaults to 1
+	Options        map[string]interface{} `json:"options,omitempty"`
+	// OnProgress, if set, is called with the provider status every time a long-running job is polled
+	OnProgress func(VideoProgress) `json:"-"`
+}
+
+// VideoProgress reports the intermediate state of a video generation job
+type VideoProgress struct {
+	Status   string `json:"status"`             // Provider status, e.g. queued, in_progress
+	Progress *int   `json:"progress,omitempty"` // Completion percentage, nil when the provider does not report one
+}
+
+func (req VideoGenerateRequest) reportProgress(status string, progress *int) {
+	if req.OnProgress != nil {
+		req.OnProgress(VideoProgress{Status: status, Progress: progress})
+	}
 }
 
 // VideoGenerateResponse defines the response for video generation
 type VideoGenerateResponse struct {
-	Data  []byte `json:"data,omitempty"` // Raw video data or URL
-	URL   string `json:"url,omitempty"`
-	Model string `json:"model"`
+	Videos []GeneratedVideo `json:"videos"` // One entry per requested video, at least one
+	Model  string           `json:"model"`
+}
+
+// GeneratedVideo is a single video of a VideoGenerateResponse, given as raw data or URL
+type GeneratedVideo struct {
+	Data []byte `json:"data,omitempty"`
+	URL  string `json:"url,omitempty"`
 }
 
 // Model represents an AI model
 type Model struct {
 	ID           string   `json:"id"`
+	DisplayName  string   `json:"display_name,omitempty"` // Set by a user override
 	Owner        string   `json:"owner,omitempty"`
 	Created      int64    `json:"created,omitempty"`
 	Object       string   `json:"object,omitempty"`
+	ProviderID   int      `json:"provider_id,omitempty"`
 	ProviderName string   `json:"provider_name,omitempty"`
 	ProviderType string   `json:"provider_type,omitempty"`
 	Input        []string `json:"input,omitempty"`
 	Output       []string `json:"output,omitempty"`
+
+	// Known from models.dev
+	ContextWindow int        `json:"context_window,omitempty"` // Tokens of input and output together
+	OutputLimit   int        `json:"output_limit,omitempty"`   // Most tokens a single response may hold
+	Cost          *ModelCost `json:"cost,omitempty"`           // USD per million tokens
+	ReleaseDate   string     `json:"release_date,omitempty"`
+	LastUpdated   string     `json:"last_updated,omitempty"`
 }
 
 // AIClient defines the interface that all AI providers must implement
 type AIClient interface {
 	GenerateText(ctx context.Context, req TextGenerateRequest) (*TextGenerateResponse, error)
+	// StreamText generates text like GenerateText but calls onDelta for each incremental chunk.
+	// The returned response holds the full content and the final usage.
+	StreamText(ctx context.Context, req TextGenerateRequest, onDelta func(delta string)) (*TextGenerateResponse, error)
 	GenerateImage(ctx context.Context, req ImageGenerateRequest) (*ImageGenerateResponse, error)
 	GenerateAudio(ctx context.Context, req AudioGenerateRequest) (*AudioGenerateResponse, error)
 	GenerateVideo(ctx context.Context, req VideoGenerateRequest) (*VideoGenerateResponse, error)
+	// Transcribe turns the speech in an audio or video file into text
+	Transcribe(ctx context.Context, req TranscribeRequest) (*TranscribeResponse, error)
+	// Embed turns texts into vectors for semantic search
+	Embed(ctx context.Context, req EmbedRequest) (*EmbedResponse, error)
 	ListModels(ctx context.Context) ([]Model, error)
 }
 
+// ErrVideoJobFailed is wrapped by WaitVideoJob when the provider reports that the job itself failed,
+// as opposed to the provider being unreachable while polling
+var ErrVideoJobFailed = errors.New("video generation failed")
+
+// VideoJobClient is implemented by clients whose video generation runs as a provider side job.
+// Splitting start and wait lets the caller persist the job ID and resume polling after a restart.
+type VideoJobClient interface {
+	// StartVideoJob submits the generation and returns the provider job ID
+	StartVideoJob(ctx context.Context, req VideoGenerateRequest) (string, error)
+	// WaitVideoJob polls the provider job until it finishes and downloads the result
+	WaitVideoJob(ctx context.Context, jobID string, req VideoGenerateRequest) (*VideoGenerateResponse, error)
+}
+
 // NewClient creates a new AI client based on the configuration
 func NewClient(config database.ModelProvider) (AIClient, error) {
 	switch config.Type {
@@ -117,7 +287,24 @@ func NewClient(config database.ModelProvider) (AIClient, error) {
 		return NewOpenAIClient(config)
 	case database.ProviderClaude:
 		return NewClaudeClient(config)
+	case database.ProviderOllama:
+		return NewOllamaClient(config)
+	case database.ProviderMock:
+		return NewMockClient(config)
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", config.Type)
 	}
 }
+
+// ChatInputs lists the input modalities a provider's client forwards in a text conversation,
+// attachments of any other modality would be dropped whatever the model supports
+func ChatInputs(providerType database.AIProvider) []string {
+	switch providerType {
+	case database.ProviderGemini, database.ProviderMock:
+		return []string{"image", "video", "audio", "pdf"}
+	case database.ProviderClaude:
+		return []string{"image", "pdf"}
+	default:
+		return []string{"image"}
+	}
+}
@@ -0,0 +1,311 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// validateSchema checks a value decoded by encoding/json against a JSON Schema.
+// It covers the subset providers accept for structured output: type, enum, const, properties,
+// required, additionalProperties, items, length and range limits, pattern, anyOf/oneOf/allOf
+// and local $ref. Unknown keywords are ignored.
+func validateSchema(value interface{}, schema map[string]interface{}) error {
+	// Schemas built in Go may hold ints and typed slices, bring them into the decoded JSON shape
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	var normalized map[string]interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+
+	v := schemaValidator{root: normalized}
+	return v.validate(value, normalized, "$", nil)
+}
+
+type schemaValidator struct {
+	root map[string]interface{}
+}
+
+// validate checks value against schema. refs are the references followed for this same value,
+// one of them coming back means the schema loops without ever reaching a keyword that checks something.
+func (v schemaValidator) validate(value interface{}, schema map[string]interface{}, path string, refs []string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		if slices.Contains(refs, ref) {
+			return fmt.Errorf("%s: $ref %q refers back to itself", path, ref)
+		}
+		resolved, err := v.resolve(ref)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return v.validate(value, resolved, path, append(slices.Clone(refs), ref))
+	}
+
+	if types, ok := schema["type"]; ok && !matchesType(value, types) {
+		return fmt.Errorf("%s: expected %v, got %s", path, types, jsonTypeName(value))
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, option := range enum {
+			if reflect.DeepEqual(option, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, value, enum)
+		}
+	}
+	if constant, ok := schema["const"]; ok && !reflect.DeepEqual(constant, value) {
+		return fmt.Errorf("%s: expected %v", path, constant)
+	}
+
+	if err := v.validateCombinators(value, schema, path, refs); err != nil {
+		return err
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return v.validateObject(value, schema, path)
+	case []interface{}:
+		return v.validateArray(value, schema, path)
+	case string:
+		return validateString(value, schema, path)
+	case float64:
+		return validateNumber(value, schema, path)
+	}
+	return nil
+}
+
+func (v schemaValidator) validateCombinators(value interface{}, schema map[string]interface{}, path string, refs []string) error {
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range all {
+			if subSchema, ok := sub.(map[string]interface{}); ok {
+				if err := v.validate(value, subSchema, path, refs); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		var firstErr error
+		matched := false
+		for _, sub := range anyOf {
+			if subSchema, ok := sub.(map[string]interface{}); ok {
+				err := v.validate(value, subSchema, path, refs)
+				if err == nil {
+					matched = true
+					break
+				}
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: matches none of anyOf: %v", path, firstErr)
+		}
+	}
+
+	if one, ok := schema["oneOf"].([]interface{}); ok {
+		matches := 0
+		for _, sub := range one {
+			if subSchema, ok := sub.(map[string]interface{}); ok && v.validate(value, subSchema, path, refs) == nil {
+				matches++
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("%s: matches %d schemas of oneOf, expected exactly 1", path, matches)
+		}
+	}
+
+	return nil
+}
+
+func (v schemaValidator) validateObject(value map[string]interface{}, schema map[string]interface{}, path string) error {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if key, ok := name.(string); ok {
+				if _, present := value[key]; !present {
+					return fmt.Errorf("%s: missing required property %q", path, key)
+				}
+			}
+		}
+	}
+
+	for key, item := range value {
+		itemPath := path + "." + key
+		if propSchema, ok := properties[key].(map[string]interface{}); ok {
+			if err := v.validate(item, propSchema, itemPath, nil); err != nil {
+				return err
+			}
+			continue
+		}
+		if _, known := properties[key]; known {
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return fmt.Errorf("%s: unexpected property %q", path, key)
+			}
+		case map[string]interface{}:
+			if err := v.validate(item, additional, itemPath, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v schemaValidator) validateArray(value []interface{}, schema map[string]interface{}, path string) error {
+	if limit, ok := schemaNumber(schema, "minItems"); ok && float64(len(value)) < limit {
+		return fmt.Errorf("%s: expected at least %v items, got %d", path, limit, len(value))
+	}
+	if limit, ok := schemaNumber(schema, "maxItems"); ok && float64(len(value)) > limit {
+		return fmt.Errorf("%s: expected at most %v items, got %d", path, limit, len(value))
+	}
+
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range value {
+			if err := v.validate(item, items, fmt.Sprintf("%s[%d]", path, i), nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateString(value string, schema map[string]interface{}, path string) error {
+	length := float64(utf8.RuneCountInString(value))
+	if limit, ok := schemaNumber(schema, "minLength"); ok && length < limit {
+		return fmt.Errorf("%s: expected at least %v characters", path, limit)
+	}
+	if limit, ok := schemaNumber(schema, "maxLength"); ok && length > limit {
+		return fmt.Errorf("%s: expected at most %v characters", path, limit)
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("%s: invalid pattern %q: %w", path, pattern, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("%s: %q does not match pattern %q", path, value, pattern)
+		}
+	}
+	return nil
+}
+
+func validateNumber(value float64, schema map[string]interface{}, path string) error {
+	if limit, ok := schemaNumber(schema, "minimum"); ok && value < limit {
+		return fmt.Errorf("%s: %v is less than the minimum %v", path, value, limit)
+	}
+	if limit, ok := schemaNumber(schema, "maximum"); ok && value > limit {
+		return fmt.Errorf("%s: %v is greater than the maximum %v", path, value, limit)
+	}
+	if limit, ok := schemaNumber(schema, "exclusiveMinimum"); ok && value <= limit {
+		return fmt.Errorf("%s: %v must be greater than %v", path, value, limit)
+	}
+	if limit, ok := schemaNumber(schema, "exclusiveMaximum"); ok && value >= limit {
+		return fmt.Errorf("%s: %v must be less than %v", path, value, limit)
+	}
+	return nil
+}
+
+// resolve follows a local reference such as "#/$defs/shot"
+func (v schemaValidator) resolve(ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("only local $ref is supported, got %q", ref)
+	}
+
+	var current interface{} = v.root
+	for _, token := range strings.Split(strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+
+		switch node := current.(type) {
+		case map[string]interface{}:
+			current = node[token]
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("unresolvable $ref %q", ref)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+	}
+
+	schema, ok := current.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unresolvable $ref %q", ref)
+	}
+	return schema, nil
+}
+
+func matchesType(value interface{}, types interface{}) bool {
+	switch types := types.(type) {
+	case string:
+		return matchesSingleType(value, types)
+	case []interface{}:
+		for _, t := range types {
+			if name, ok := t.(string); ok && matchesSingleType(value, name) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesSingleType(value interface{}, typeName string) bool {
+	switch typeName {
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonTypeName(value) == typeName
+	}
+}
+
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func schemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	number, ok := schema[key].(float64)
+	return number, ok
+}
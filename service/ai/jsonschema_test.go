@@ -0,0 +1,116 @@
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidateSchema(t *testing.T) {
+	shot := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"title":    map[string]interface{}{"type": "string", "minLength": 1, "maxLength": 10},
+			"seconds":  map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 8},
+			"mood":     map[string]interface{}{"enum": []string{"calm", "tense"}},
+			"camera":   map[string]interface{}{"type": "string", "pattern": "^[a-z]+$"},
+			"optional": map[string]interface{}{"type": []string{"string", "null"}},
+		},
+		"required":             []string{"title", "seconds"},
+		"additionalProperties": false,
+	}
+	withRefs := map[string]interface{}{
+		"$defs": map[string]interface{}{"shot": shot},
+		"type":  "object",
+		"properties": map[string]interface{}{
+			"shots": map[string]interface{}{
+				"type":     "array",
+				"minItems": 1,
+				"maxItems": 2,
+				"items":    map[string]interface{}{"$ref": "#/$defs/shot"},
+			},
+		},
+	}
+	combinators := map[string]interface{}{
+		"anyOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{"type": "number", "exclusiveMinimum": 0},
+		},
+		"oneOf": []interface{}{
+			map[string]interface{}{"type": "number"},
+			map[string]interface{}{"type": "integer"},
+			map[string]interface{}{"type": "string"},
+		},
+	}
+	refCycle := map[string]interface{}{
+		"$defs": map[string]interface{}{
+			"a": map[string]interface{}{"$ref": "#/$defs/b"},
+			"b": map[string]interface{}{"$ref": "#/$defs/a"},
+		},
+		"$ref": "#/$defs/a",
+	}
+	anyOfCycle := map[string]interface{}{
+		"$defs": map[string]interface{}{
+			"a": map[string]interface{}{"anyOf": []interface{}{map[string]interface{}{"$ref": "#/$defs/a"}}},
+		},
+		"$ref": "#/$defs/a",
+	}
+	// A schema may refer to itself for nested values, only a loop on the same value is an error
+	tree := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"children": map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		schema  map[string]interface{}
+		wantErr string // Empty when the value is valid
+	}{
+		{"valid object", `{"title":"Intro","seconds":4,"mood":"calm","optional":null}`, shot, ""},
+		{"missing required", `{"title":"Intro"}`, shot, `missing required property "seconds"`},
+		{"wrong type", `{"title":3,"seconds":4}`, shot, "$.title: expected string, got number"},
+		{"integer with fraction", `{"title":"a","seconds":4.5}`, shot, "expected integer"},
+		{"below minimum", `{"title":"a","seconds":0}`, shot, "less than the minimum"},
+		{"above maximum", `{"title":"a","seconds":9}`, shot, "greater than the maximum"},
+		{"too long", `{"title":"abcdefghijk","seconds":1}`, shot, "at most 10 characters"},
+		{"length counts runes", `{"title":"ééééééééé","seconds":1}`, shot, ""},
+		{"not in enum", `{"title":"a","seconds":1,"mood":"happy"}`, shot, "is not one of"},
+		{"pattern mismatch", `{"title":"a","seconds":1,"camera":"Pan"}`, shot, "does not match pattern"},
+		{"unexpected property", `{"title":"a","seconds":1,"extra":true}`, shot, `unexpected property "extra"`},
+		{"ref items", `{"shots":[{"title":"a","seconds":1}]}`, withRefs, ""},
+		{"ref item error has path", `{"shots":[{"title":"a","seconds":1},{"title":"b"}]}`, withRefs, "$.shots[1]: missing required property"},
+		{"too few items", `{"shots":[]}`, withRefs, "at least 1 items"},
+		{"too many items", `{"shots":[{"title":"a","seconds":1},{"title":"a","seconds":1},{"title":"a","seconds":1}]}`, withRefs, "at most 2 items"},
+		{"anyOf and oneOf match", `"text"`, combinators, ""},
+		{"anyOf matches none", `-1.5`, combinators, "matches none of anyOf"},
+		{"oneOf matches two", `2`, combinators, "matches 2 schemas of oneOf"},
+		{"unresolvable ref", `{}`, map[string]interface{}{"$ref": "#/$defs/missing"}, "unresolvable $ref"},
+		{"remote ref", `{}`, map[string]interface{}{"$ref": "https://example.com/schema.json"}, "only local $ref"},
+		{"ref to the root", `{}`, map[string]interface{}{"$ref": "#"}, `$ref "#" refers back to itself`},
+		{"ref cycle", `{}`, refCycle, `$ref "#/$defs/a" refers back to itself`},
+		{"ref cycle through anyOf", `1`, anyOfCycle, "refers back to itself"},
+		{"recursive schema on nested data", `{"children":[{"children":[]}]}`, tree, ""},
+		{"recursive schema error has path", `{"children":[{"children":[1]}]}`, tree, "$.children[0].children[0]: expected object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value interface{}
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatalf("invalid test value: %v", err)
+			}
+			err := validateSchema(value, tt.schema)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && err == nil:
+				t.Errorf("expected an error containing %q", tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Errorf("error %q does not contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,732 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	"image/png"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"visionflow/database"
+)
+
+// mockVideo is a 2 second 128x72 H.264 clip showing a static gradient
+//
+//go:embed mock_video.mp4
+var mockVideo []byte
+
+// mockCallCounters counts calls per provider so fail_every stays deterministic across client instances
+var mockCallCounters sync.Map
+
+// MockClient implements the AIClient interface without any network access.
+// Every result is derived from the request only, so the same request always gives the same output.
+//
+// It is configured through the provider's Base URL as query parameters, e.g. "mock://?latency=2s&fail_every=3":
+//   - latency: delay added to every call, e.g. 500ms
+//   - fail: comma separated kinds that always fail (text, image, audio, video, transcribe, embed, models)
+//   - fail_every: every Nth call fails
+//   - fail_status: HTTP status of injected failures, default 500 (429 and 5xx are retried)
+type MockClient struct {
+	config     database.ModelProvider
+	retry      RetryPolicy
+	latency    time.Duration
+	failKinds  map[string]bool
+	failEvery  int64
+	failStatus int
+}
+
+// NewMockClient creates a new mock client
+func NewMockClient(config database.ModelProvider) (*MockClient, error) {
+	query := config.BaseURL
+	if i := strings.Index(query, "?"); i >= 0 {
+		query = query[i+1:]
+	}
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mock configuration %q: %w", config.BaseURL, err)
+	}
+
+	c := &MockClient{
+		config:     config,
+		retry:      retryPolicyFor(config),
+		failKinds:  map[string]bool{},
+		failStatus: http.StatusInternalServerError,
+	}
+
+	if value := params.Get("latency"); value != "" {
+		if c.latency, err = time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("invalid mock latency %q: %w", value, err)
+		}
+	}
+	for _, kind := range strings.Split(params.Get("fail"), ",") {
+		if kind = strings.TrimSpace(kind); kind != "" {
+			c.failKinds[kind] = true
+		}
+	}
+	if value := params.Get("fail_every"); value != "" {
+		if c.failEvery, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid mock fail_every %q: %w", value, err)
+		}
+	}
+	if value := params.Get("fail_status"); value != "" {
+		if c.failStatus, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("invalid mock fail_status %q: %w", value, err)
+		}
+	}
+
+	return c, nil
+}
+
+// call waits for the configured latency and then injects the configured failures
+func (c *MockClient) call(ctx context.Context, kind string) error {
+	if err := sleepContext(ctx, c.latency); err != nil {
+		return err
+	}
+	return c.inject(kind)
+}
+
+// inject counts the attempt and fails it when the configuration asks for it
+func (c *MockClient) inject(kind string) error {
+	counter, _ := mockCallCounters.LoadOrStore(c.config.ID, new(atomic.Int64))
+	n := counter.(*atomic.Int64).Add(1)
+
+	if c.failKinds[kind] || (c.failEvery > 0 && n%c.failEvery == 0) {
+		return fmt.Errorf("mock %s call %d failed: %w", kind, n, &HTTPStatusError{StatusCode: c.failStatus, Body: "injected failure"})
+	}
+	return nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+func mockModel(model, fallback string) string {
+	if model == "" {
+		return fallback
+	}
+	return model
+}
+
+// mockText builds the placeholder answer for a text request
+func mockText(req TextGenerateRequest) string {
+	prompt := req.Prompt
+	if runes := []rune(prompt); len(runes) > 200 {
+		prompt = string(runes[:200]) + "..."
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Mock response from %s.\n\nPrompt: %s", mockModel(req.Model, "mock-text"), prompt)
+	if req.System != "" {
+		fmt.Fprintf(&b, "\nSystem: %s", req.System)
+	}
+	if len(req.Messages) > 0 {
+		fmt.Fprintf(&b, "\nEarlier messages: %d", len(req.Messages))
+		if last := req.Messages[len(req.Messages)-1]; last.Role == RoleTool {
+			for _, result := range last.ToolResults {
+				fmt.Fprintf(&b, "\nTool %s: %s", result.Name, result.Content)
+			}
+		}
+	}
+
+	var attachments []string
+	for _, a := range []struct {
+		name  string
+		count int
+	}{
+		{"image", len(req.Images)},
+		{"video", len(req.Videos)},
+		{"audio", len(req.Audios)},
+		{"document", len(req.Documents)},
+	} {
+		if a.count > 0 {
+			attachments = append(attachments, fmt.Sprintf("%d %s", a.count, a.name))
+		}
+	}
+	if len(attachments) > 0 {
+		fmt.Fprintf(&b, "\nAttachments: %s", strings.Join(attachments, ", "))
+	}
+	return b.String()
+}
+
+func mockTextResponse(req TextGenerateRequest, content string) *TextGenerateResponse {
+	promptTokens := len(strings.Fields(req.Prompt))
+	outputTokens := len(strings.Fields(content))
+	return &TextGenerateResponse{
+		Content:      content,
+		PromptTokens: promptTokens,
+		OutputTokens: outputTokens,
+		TotalTokens:  promptTokens + outputTokens,
+		Model:        mockModel(req.Model, "mock-text"),
+	}
+}
+
+// mockContent returns the placeholder answer, or a sample document when structured output is requested
+func mockContent(req TextGenerateRequest) (string, error) {
+	if _, err := req.conversation(); err != nil {
+		return "", err
+	}
+
+	format := req.ResponseFormat
+	if !format.structured() {
+		return mockText(req), nil
+	}
+	if len(format.Schema) == 0 {
+		data, err := json.Marshal(map[string]string{"response": mockText(req)})
+		return string(data), err
+	}
+
+	data, err := json.Marshal(format.Schema)
+	if err != nil {
+		return "", fmt.Errorf("invalid response schema: %w", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return "", fmt.Errorf("invalid response schema: %w", err)
+	}
+
+	sample, err := json.MarshalIndent(mockSample(schemaValidator{root: schema}, schema, 0), "", "  ")
+	return string(sample), err
+}
+
+// mockToolCalls calls the forced or first tool with sample arguments, unless the conversation
+// already ends with tool results, so a tool loop always finishes after one round.
+func mockToolCalls(req TextGenerateRequest) ([]ToolCall, error) {
+	if len(req.Tools) == 0 || req.ToolChoice == ToolChoiceNone {
+		return nil, nil
+	}
+	if err := req.validateTools(); err != nil {
+		return nil, err
+	}
+	conversation, err := req.conversation()
+	if err != nil {
+		return nil, err
+	}
+	if conversation[len(conversation)-1].Role == RoleTool {
+		return nil, nil
+	}
+
+	tool := req.Tools[0]
+	for _, candidate := range req.Tools {
+		if candidate.Name == req.ToolChoice {
+			tool = candidate
+		}
+	}
+
+	data, err := json.Marshal(tool.toolParameters())
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for tool %s: %w", tool.Name, err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid parameters for tool %s: %w", tool.Name, err)
+	}
+	arguments, err := json.Marshal(mockSample(schemaValidator{root: schema}, schema, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	return []ToolCall{{ID: fmt.Sprintf("call_mock_%d", len(conversation)), Name: tool.Name, Arguments: string(arguments)}}, nil
+}
+
+// mockSample builds the smallest document satisfying the common schema keywords
+func mockSample(v schemaValidator, schema map[string]interface{}, depth int) interface{} {
+	if depth > 16 {
+		return nil
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		if resolved, err := v.resolve(ref); err == nil {
+			return mockSample(v, resolved, depth+1)
+		}
+	}
+	if constant, ok := schema["const"]; ok {
+		return constant
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	for _, key := range []string{"anyOf", "oneOf", "allOf"} {
+		if options, ok := schema[key].([]interface{}); ok && len(options) > 0 {
+			if option, ok := options[0].(map[string]interface{}); ok {
+				return mockSample(v, option, depth+1)
+			}
+		}
+	}
+
+	typeName, _ := schema["type"].(string)
+	if types, ok := schema["type"].([]interface{}); ok && len(types) > 0 {
+		typeName, _ = types[0].(string)
+	}
+
+	switch typeName {
+	case "object":
+		object := map[string]interface{}{}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, property := range properties {
+			if propSchema, ok := property.(map[string]interface{}); ok {
+				object[name] = mockSample(v, propSchema, depth+1)
+			}
+		}
+		return object
+	case "array":
+		count := 1
+		if minItems, ok := schemaNumber(schema, "minItems"); ok && int(minItems) > count {
+			count = int(minItems)
+		}
+		items, _ := schema["items"].(map[string]interface{})
+		array := make([]interface{}, count)
+		for i := range array {
+			array[i] = mockSample(v, items, depth+1)
+		}
+		return array
+	case "integer", "number":
+		if minimum, ok := schemaNumber(schema, "minimum"); ok {
+			return minimum
+		}
+		return 1
+	case "boolean":
+		return true
+	case "null":
+		return nil
+	default:
+		sample := "mock"
+		if minLength, ok := schemaNumber(schema, "minLength"); ok && int(minLength) > len(sample) {
+			sample = strings.Repeat("m", int(minLength))
+		}
+		return sample
+	}
+}
+
+// GenerateText returns a placeholder answer that echoes the prompt
+func (c *MockClient) GenerateText(ctx context.Context, req TextGenerateRequest) (*TextGenerateResponse, error) {
+	if err := req.ResponseFormat.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := resolveOptions(database.ProviderMock, req.Model, req.Options); err != nil {
+		return nil, err
+	}
+
+	result, err := withRetry(ctx, c.retry, func(ctx context.Context) (*TextGenerateResponse, error) {
+		if err := c.call(ctx, "text"); err != nil {
+			return nil, err
+		}
+		calls, err := mockToolCalls(req)
+		if err != nil {
+			return nil, &permanentError{err}
+		}
+		if len(calls) > 0 {
+			result := mockTextResponse(req, "")
+			result.ToolCalls = calls
+			return result, nil
+		}
+		content, err := mockContent(req)
+		if err != nil {
+			return nil, &permanentError{err}
+		}
+		return mockTextResponse(req, content), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStructuredOutput(req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamText emits the placeholder answer word by word, spreading the latency over the chunks
+func (c *MockClient) StreamText(ctx context.Context, req TextGenerateRequest, onDelta func(delta string)) (*TextGenerateResponse, error) {
+	if err := req.ResponseFormat.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := resolveOptions(database.ProviderMock, req.Model, req.Options); err != nil {
+		return nil, err
+	}
+
+	result, err := withRetry(ctx, c.retry, func(ctx context.Context) (*TextGenerateResponse, error) {
+		if err := c.inject("text"); err != nil {
+			return nil, err
+		}
+
+		// Tool calls are not streamed, they arrive with the final response
+		calls, err := mockToolCalls(req)
+		if err != nil {
+			return nil, &permanentError{err}
+		}
+		if len(calls) > 0 {
+			if err := sleepContext(ctx, c.latency); err != nil {
+				return nil, &permanentError{err}
+			}
+			result := mockTextResponse(req, "")
+			result.ToolCalls = calls
+			return result, nil
+		}
+
+		content, err := mockContent(req)
+		if err != nil {
+			return nil, &permanentError{err}
+		}
+		chunks := strings.SplitAfter(content, " ")
+		delay := c.latency / time.Duration(len(chunks))
+		for _, chunk := range chunks {
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, &permanentError{err}
+			}
+			onDelta(chunk)
+		}
+		return mockTextResponse(req, content), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStructuredOutput(req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GenerateImage renders PNG gradients whose colors are derived from the prompt, one per requested image.
+// With an input image the gradient is painted over the masked region, or blended with the whole image.
+func (c *MockClient) GenerateImage(ctx context.Context, req ImageGenerateRequest) (*ImageGenerateResponse, error) {
+	if _, err := resolveOptions(database.ProviderMock, req.Model, req.Options); err != nil {
+		return nil, err
+	}
+	width, height := 512, 512
+	if req.Size != "" {
+		if _, err := fmt.Sscanf(req.Size, "%dx%d", &width, &height); err != nil {
+			return nil, fmt.Errorf("invalid image size %q: %w", req.Size, err)
+		}
+		if width < 16 || height < 16 || width > 2048 || height > 2048 {
+			return nil, fmt.Errorf("image size %q must be between 16x16 and 2048x2048", req.Size)
+		}
+	}
+
+	var base, mask image.Image
+	if len(req.Images) > 0 {
+		var err error
+		if base, err = mockDecodeImage(req.Images[0]); err != nil {
+			return nil, err
+		}
+		width, height = base.Bounds().Dx(), base.Bounds().Dy()
+	}
+	if req.Mask != "" {
+		if base == nil {
+			return nil, errors.New("a mask needs an input image to edit")
+		}
+		var err error
+		if mask, err = mockDecodeImage(req.Mask); err != nil {
+			return nil, err
+		}
+		if mask.Bounds().Size() != base.Bounds().Size() {
+			return nil, fmt.Errorf("mask is %v but the image is %v", mask.Bounds().Size(), base.Bounds().Size())
+		}
+	}
+
+	n := max(req.N, 1)
+	if n > 10 {
+		return nil, fmt.Errorf("at most 10 images per request, got %d", n)
+	}
+
+	return withRetry(ctx, c.retry, func(ctx context.Context) (*ImageGenerateResponse, error) {
+		if err := c.call(ctx, "image"); err != nil {
+			return nil, err
+		}
+
+		images := make([]GeneratedImage, 0, n)
+		for i := 0; i < n; i++ {
+			// Every candidate gets its own colors, the first matches a single-image request
+			hash := fnv.New64a()
+			hash.Write([]byte(req.Prompt))
+			if i > 0 {
+				fmt.Fprintf(hash, "#%d", i)
+			}
+			data, err := mockImage(hash.Sum64(), width, height, base, mask)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, GeneratedImage{Data: data, RevisedPrompt: req.Prompt})
+		}
+
+		return &ImageGenerateResponse{
+			Images: images,
+			Model:  mockModel(req.Model, "mock-image"),
+		}, nil
+	})
+}
+
+// mockImage renders a gradient PNG with colors taken from sum, painted over base if given
+func mockImage(sum uint64, width, height int, base, mask image.Image) ([]byte, error) {
+	from := color.RGBA{R: uint8(sum), G: uint8(sum >> 8), B: uint8(sum >> 16), A: 255}
+	to := color.RGBA{R: uint8(sum >> 24), G: uint8(sum >> 32), B: uint8(sum >> 40), A: 255}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			t := float64(x+y) / float64(width+height-2)
+			paint := color.RGBA{
+				R: uint8(float64(from.R) + (float64(to.R)-float64(from.R))*t),
+				G: uint8(float64(from.G) + (float64(to.G)-float64(from.G))*t),
+				B: uint8(float64(from.B) + (float64(to.B)-float64(from.B))*t),
+				A: 255,
+			}
+			if base != nil {
+				orig := color.RGBAModel.Convert(base.At(base.Bounds().Min.X+x, base.Bounds().Min.Y+y)).(color.RGBA)
+				if mask == nil {
+					paint = color.RGBA{R: orig.R/2 + paint.R/2, G: orig.G/2 + paint.G/2, B: orig.B/2 + paint.B/2, A: 255}
+				} else if _, _, _, a := mask.At(mask.Bounds().Min.X+x, mask.Bounds().Min.Y+y).RGBA(); a != 0 {
+					paint = orig
+				}
+			}
+			img.SetRGBA(x, y, paint)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode mock image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// mockDecodeImage loads a PNG or JPEG input image
+func mockDecodeImage(pathOrURL string) (image.Image, error) {
+	data, err := LoadContent(pathOrURL)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image %s: %w", ContentName(pathOrURL), err)
+	}
+	return img, nil
+}
+
+// GenerateAudio returns silent WAV audio whose length follows the prompt length (1 to 10 seconds)
+func (c *MockClient) GenerateAudio(ctx context.Context, req AudioGenerateRequest) (*AudioGenerateResponse, error) {
+	if _, err := resolveOptions(database.ProviderMock, req.Model, req.Options); err != nil {
+		return nil, err
+	}
+	return withRetry(ctx, c.retry, func(ctx context.Context) (*AudioGenerateResponse, error) {
+		if err := c.call(ctx, "audio"); err != nil {
+			return nil, err
+		}
+
+		// Roughly the pace of speech, 15 characters per second
+		seconds := float64(len([]rune(req.Prompt))) / 15
+		if req.Speed != nil && *req.Speed > 0 {
+			seconds /= *req.Speed
+		}
+		seconds = min(max(seconds, 1), 10)
+
+		return &AudioGenerateResponse{
+			Data:   silentWAV(seconds),
+			Format: "wav",
+			Model:  mockModel(req.Model, "mock-audio"),
+		}, nil
+	})
+}
+
+// silentWAV encodes the given duration of 16 kHz mono 16-bit PCM silence
+func silentWAV(seconds float64) []byte {
+	const sampleRate = 16000
+	dataSize := uint32(seconds*sampleRate) * 2
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+	binary.Write(&buf, binary.LittleEndian, uint32(16))           // fmt chunk size
+	binary.Write(&buf, binary.LittleEndian, uint16(1))            // PCM
+	binary.Write(&buf, binary.LittleEndian, uint16(1))            // mono
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))   // sample rate
+	binary.Write(&buf, binary.LittleEndian, uint32(sampleRate*2)) // byte rate
+	binary.Write(&buf, binary.LittleEndian, uint16(2))            // block align
+	binary.Write(&buf, binary.LittleEndian, uint16(16))           // bits per sample
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, dataSize)
+	buf.Write(make([]byte, dataSize))
+	return buf.Bytes()
+}
+
+// mockMediaSeconds reads the length of a PCM WAV file, other formats are assumed to be 5 seconds long
+func mockMediaSeconds(data []byte) float64 {
+	if len(data) >= 44 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WAVE" {
+		byteRate := binary.LittleEndian.Uint32(data[28:32])
+		if byteRate > 0 {
+			return float64(len(data)-44) / float64(byteRate)
+		}
+	}
+	return 5
+}
+
+// Transcribe returns a placeholder transcript with one segment per started 5 seconds of the media
+func (c *MockClient) Transcribe(ctx context.Context, req TranscribeRequest) (*TranscribeResponse, error) {
+	if _, err := resolveOptions(database.ProviderMock, req.Model, req.Options); err != nil {
+		return nil, err
+	}
+	return withRetry(ctx, c.retry, func(ctx context.Context) (*TranscribeResponse, error) {
+		if err := c.call(ctx, "transcribe"); err != nil {
+			return nil, err
+		}
+
+		data, err := LoadContent(req.Media)
+		if err != nil {
+			return nil, &permanentError{err}
+		}
+
+		const segmentSeconds = 5
+		duration := mockMediaSeconds(data)
+		result := &TranscribeResponse{
+			Language: req.Language,
+			Duration: duration,
+			Model:    mockModel(req.Model, "mock-transcribe"),
+		}
+		if result.Language == "" {
+			result.Language = "en"
+		}
+
+		name := ContentName(req.Media)
+		var texts []string
+		for start := 0.0; start < duration; start += segmentSeconds {
+			segment := TranscriptSegment{
+				Start: start,
+				End:   min(start+segmentSeconds, duration),
+				Text:  fmt.Sprintf("Mock transcript of %s, part %d.", name, len(result.Segments)+1),
+			}
+			result.Segments = append(result.Segments, segment)
+			texts = append(texts, segment.Text)
+		}
+		result.Text = strings.Join(texts, " ")
+		return result, nil
+	})
+}
+
+// mockEmbeddingSize is the length of mock vectors
+const mockEmbeddingSize = 64
+
+// Embed returns normalized bag-of-words vectors, texts sharing words get similar vectors
+func (c *MockClient) Embed(ctx context.Context, req EmbedRequest) (*EmbedResponse, error) {
+	return withRetry(ctx, c.retry, func(ctx context.Context) (*EmbedResponse, error) {
+		if err := c.call(ctx, "embed"); err != nil {
+			return nil, err
+		}
+
+		size := mockEmbeddingSize
+		if req.Dimensions > 0 {
+			size = req.Dimensions
+		}
+
+		result := &EmbedResponse{Model: mockModel(req.Model, "mock-embedding")}
+		for _, input := range req.Inputs {
+			vector := make([]float32, size)
+			words := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+				return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+			})
+			for _, word := range words {
+				h := fnv.New32a()
+				h.Write([]byte(word))
+				vector[h.Sum32()%uint32(size)]++
+			}
+
+			var norm float64
+			for _, v := range vector {
+				norm += float64(v * v)
+			}
+			if norm > 0 {
+				norm = math.Sqrt(norm)
+				for i := range vector {
+					vector[i] = float32(float64(vector[i]) / norm)
+				}
+			}
+
+			result.Embeddings = append(result.Embeddings, vector)
+			result.PromptTokens += len(words)
+		}
+		return result, nil
+	})
+}
+
+// GenerateVideo returns the embedded fixture clip once per requested video, reporting progress while the latency elapses
+func (c *MockClient) GenerateVideo(ctx context.Context, req VideoGenerateRequest) (*VideoGenerateResponse, error) {
+	if _, err := resolveOptions(database.ProviderMock, req.Model, req.Options); err != nil {
+		return nil, err
+	}
+	return withRetry(ctx, c.retry, func(ctx context.Context) (*VideoGenerateResponse, error) {
+		const steps = 4
+		for step := 0; step < steps; step++ {
+			progress := step * 100 / steps
+			req.reportProgress("in_progress", &progress)
+			if err := sleepContext(ctx, c.latency/steps); err != nil {
+				return nil, err
+			}
+		}
+		if err := c.inject("video"); err != nil {
+			return nil, err
+		}
+
+		progress := 100
+		req.reportProgress("completed", &progress)
+
+		videos := make([]GeneratedVideo, max(req.N, 1))
+		for i := range videos {
+			videos[i].Data = mockVideo
+		}
+		return &VideoGenerateResponse{
+			Videos: videos,
+			Model:  mockModel(req.Model, "mock-video"),
+		}, nil
+	})
+}
+
+// ListModels lists one mock model per output modality
+func (c *MockClient) ListModels(ctx context.Context) ([]Model, error) {
+	models, err := withRetry(ctx, c.retry, func(ctx context.Context) ([]Model, error) {
+		if err := c.call(ctx, "models"); err != nil {
+			return nil, err
+		}
+
+		models := []Model{
+			{ID: "mock-text", Input: []string{"text", "image", "video", "audio", "pdf"}, Output: []string{"text"}},
+			{ID: "mock-image", Input: []string{"text", "image"}, Output: []string{"image"}},
+			{ID: "mock-audio", Input: []string{"text"}, Output: []string{"audio"}},
+			{ID: "mock-video", Input: []string{"text", "image"}, Output: []string{"video"}},
+			{ID: "mock-transcribe", Input: []string{"audio", "video"}, Output: []string{"text"}},
+			{ID: "mock-embedding", Input: []string{"text"}, Output: []string{"embedding"}},
+		}
+		for i := range models {
+			models[i].Object = "model"
+			models[i].ProviderName = c.config.Name
+			models[i].ProviderType = string(c.config.Type)
+		}
+		return models, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return annotateModels(c.config, models), nil
+}
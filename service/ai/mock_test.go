@@ -0,0 +1,204 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"visionflow/database"
+)
+
+// mockProviderID keeps the call counters of the tests apart, they are shared per provider ID
+var mockProviderID atomic.Int64
+
+func newMock(t *testing.T, baseURL string) (*MockClient, int) {
+	t.Helper()
+	id := int(1_000_000 + mockProviderID.Add(1))
+	client, err := NewMockClient(database.ModelProvider{ID: id, Type: database.ProviderMock, BaseURL: baseURL})
+	if err != nil {
+		t.Fatalf("NewMockClient(%q): %v", baseURL, err)
+	}
+	return client, id
+}
+
+// mockCalls returns how many attempts, retries included, reached a mock provider
+func mockCalls(id int) int64 {
+	counter, ok := mockCallCounters.Load(id)
+	if !ok {
+		return 0
+	}
+	return counter.(*atomic.Int64).Load()
+}
+
+func TestMockDeterministic(t *testing.T) {
+	client, _ := newMock(t, "")
+	ctx := context.Background()
+
+	textReq := TextGenerateRequest{Model: "mock-text", Prompt: "A lighthouse at dusk", System: "Be brief"}
+	first, err := client.GenerateText(ctx, textReq)
+	if err != nil {
+		t.Fatalf("GenerateText: %v", err)
+	}
+	second, err := client.GenerateText(ctx, textReq)
+	if err != nil {
+		t.Fatalf("GenerateText: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("the same text request gave %+v and %+v", first, second)
+	}
+
+	imageReq := ImageGenerateRequest{Prompt: "A lighthouse at dusk", Size: "64x32"}
+	a, err := client.GenerateImage(ctx, imageReq)
+	if err != nil {
+		t.Fatalf("GenerateImage: %v", err)
+	}
+	b, err := client.GenerateImage(ctx, imageReq)
+	if err != nil {
+		t.Fatalf("GenerateImage: %v", err)
+	}
+	if !bytes.Equal(a.Images[0].Data, b.Images[0].Data) {
+		t.Error("the same image request gave different images")
+	}
+	imageReq.Prompt = "A lighthouse at dawn"
+	c, err := client.GenerateImage(ctx, imageReq)
+	if err != nil {
+		t.Fatalf("GenerateImage: %v", err)
+	}
+	if bytes.Equal(a.Images[0].Data, c.Images[0].Data) {
+		t.Error("different prompts gave the same image")
+	}
+
+	embedReq := EmbedRequest{Inputs: []string{"red boat", "red boat", "green field"}}
+	embeddings, err := client.Embed(ctx, embedReq)
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if !reflect.DeepEqual(embeddings.Embeddings[0], embeddings.Embeddings[1]) || reflect.DeepEqual(embeddings.Embeddings[0], embeddings.Embeddings[2]) {
+		t.Error("embeddings do not follow the input text")
+	}
+}
+
+func TestMockLatency(t *testing.T) {
+	client, _ := newMock(t, "mock://?latency=50ms")
+
+	start := time.Now()
+	if _, err := client.GenerateText(context.Background(), TextGenerateRequest{Prompt: "hi"}); err != nil {
+		t.Fatalf("GenerateText: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("call returned after %v, want at least the 50ms latency", elapsed)
+	}
+
+	// The latency is cut short by the caller's context
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := client.GenerateText(ctx, TextGenerateRequest{Prompt: "hi"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want the context deadline", err)
+	}
+}
+
+func TestMockFailureInjection(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fail kinds always fail and are retried", func(t *testing.T) {
+		client, id := newMock(t, "mock://?fail=image")
+		_, err := client.GenerateImage(ctx, ImageGenerateRequest{Prompt: "x", Size: "16x16"})
+		var statusErr *HTTPStatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != 500 {
+			t.Fatalf("error = %v, want an injected status 500", err)
+		}
+		if calls := mockCalls(id); calls != 3 {
+			t.Errorf("%d attempts, want the 3 of the mock retry policy", calls)
+		}
+		// Other kinds are not affected
+		if _, err := client.GenerateText(ctx, TextGenerateRequest{Prompt: "x"}); err != nil {
+			t.Errorf("GenerateText: %v", err)
+		}
+	})
+
+	t.Run("fail_every fails every Nth attempt", func(t *testing.T) {
+		client, id := newMock(t, "mock://?fail_every=2")
+		for i := 0; i < 2; i++ {
+			// The second attempt fails and its retry succeeds
+			if _, err := client.GenerateText(ctx, TextGenerateRequest{Prompt: "x"}); err != nil {
+				t.Fatalf("call %d: %v", i, err)
+			}
+		}
+		if calls := mockCalls(id); calls != 3 {
+			t.Errorf("%d attempts, want 3", calls)
+		}
+	})
+
+	t.Run("fail_status decides whether the failure is retried", func(t *testing.T) {
+		client, id := newMock(t, "mock://?fail=text&fail_status=400")
+		_, err := client.GenerateText(ctx, TextGenerateRequest{Prompt: "x"})
+		var statusErr *HTTPStatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != 400 {
+			t.Fatalf("error = %v, want an injected status 400", err)
+		}
+		if calls := mockCalls(id); calls != 1 {
+			t.Errorf("%d attempts, a 400 must not be retried", calls)
+		}
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		for _, baseURL := range []string{"mock://?latency=soon", "mock://?fail_every=often", "mock://?fail_status=bad"} {
+			if _, err := NewMockClient(database.ModelProvider{Type: database.ProviderMock, BaseURL: baseURL}); err == nil {
+				t.Errorf("NewMockClient(%q) accepted an invalid value", baseURL)
+			}
+		}
+	})
+}
+
+func TestMockOutputsDecode(t *testing.T) {
+	client, _ := newMock(t, "")
+	ctx := context.Background()
+
+	images, err := client.GenerateImage(ctx, ImageGenerateRequest{Prompt: "x", Size: "40x24", N: 2})
+	if err != nil {
+		t.Fatalf("GenerateImage: %v", err)
+	}
+	if len(images.Images) != 2 {
+		t.Fatalf("got %d images, want 2", len(images.Images))
+	}
+	for i, generated := range images.Images {
+		img, err := png.Decode(bytes.NewReader(generated.Data))
+		if err != nil {
+			t.Fatalf("image %d is not a PNG: %v", i, err)
+		}
+		if size := img.Bounds().Size(); size.X != 40 || size.Y != 24 {
+			t.Errorf("image %d is %v, want 40x24", i, size)
+		}
+	}
+
+	audio, err := client.GenerateAudio(ctx, AudioGenerateRequest{Prompt: strings.Repeat("a", 30)})
+	if err != nil {
+		t.Fatalf("GenerateAudio: %v", err)
+	}
+	data := audio.Data
+	if audio.Format != "wav" || len(data) < 44 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		t.Fatalf("audio is not a WAV file: format %q, %d bytes", audio.Format, len(data))
+	}
+	if seconds := mockMediaSeconds(data); seconds != 2 {
+		t.Errorf("audio lasts %v seconds, want 2 for 30 characters", seconds)
+	}
+
+	videos, err := client.GenerateVideo(ctx, VideoGenerateRequest{Prompt: "x", N: 2})
+	if err != nil {
+		t.Fatalf("GenerateVideo: %v", err)
+	}
+	if len(videos.Videos) != 2 {
+		t.Fatalf("got %d videos, want 2", len(videos.Videos))
+	}
+	for i, video := range videos.Videos {
+		if len(video.Data) < 12 || string(video.Data[4:8]) != "ftyp" {
+			t.Errorf("video %d is not an MP4 file", i)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"visionflow/database"
+)
+
+// annotateModels tags listed models with their provider, adds the models.dev limits, pricing and dates,
+// and merges the user's overrides over the models.dev data, so models it does not know can still be picked by modality
+func annotateModels(config database.ModelProvider, models []Model) []Model {
+	for i := range models {
+		models[i].ProviderID = config.ID
+		if caps, ok := lookupModelCapabilities(models[i].ID); ok {
+			models[i].applyCapabilities(caps)
+		}
+	}
+	if database.DB == nil || config.ID == 0 {
+		return models
+	}
+
+	overrides, err := database.ListModelOverrides(config.ID)
+	if err != nil {
+		fmt.Printf("failed to load model overrides for %s: %v\n", config.Name, err)
+		return models
+	}
+	for _, override := range overrides {
+		for i := range models {
+			if sameModelID(models[i].ID, override.ModelID) {
+				models[i].applyOverride(override)
+			}
+		}
+	}
+	return models
+}
+
+// DescribeModel returns what is known about a provider's model from models.dev and the user's overrides,
+// without asking the provider to list its models
+func DescribeModel(config database.ModelProvider, modelID string) Model {
+	model := Model{
+		ID:           modelID,
+		ProviderName: config.Name,
+		ProviderType: string(config.Type),
+	}
+	if caps, ok := lookupModelCapabilities(modelID); ok {
+		model.Input, model.Output = caps.Input, caps.Output
+	}
+	return annotateModels(config, []Model{model})[0]
+}
+
+// lookupModelCapabilities finds the models.dev entry of a listed model ID in the forms providers use
+func lookupModelCapabilities(modelID string) (ModelCapabilities, bool) {
+	id := strings.TrimPrefix(modelID, "models/")
+	if caps, ok := lookupCapabilities(id); ok {
+		return caps, true
+	}
+	// Local Ollama tags look like "llama3.2:3b", models.dev only knows the base name
+	if base, _, found := strings.Cut(id, ":"); found {
+		return lookupCapabilities(base)
+	}
+	return ModelCapabilities{}, false
+}
+
+// applyCapabilities copies the models.dev limits, pricing and dates
+func (m *Model) applyCapabilities(caps ModelCapabilities) {
+	if caps.Limit != nil {
+		m.ContextWindow = caps.Limit.Context
+		m.OutputLimit = caps.Limit.Output
+	}
+	m.Cost = caps.Cost
+	m.ReleaseDate = caps.ReleaseDate
+	m.LastUpdated = caps.LastUpdated
+}
+
+// applyOverride replaces the fields the override sets
+func (m *Model) applyOverride(override database.ModelOverride) {
+	if override.DisplayName != "" {
+		m.DisplayName = override.DisplayName
+	}
+	if len(override.Input) > 0 {
+		m.Input = override.Input
+	}
+	if len(override.Output) > 0 {
+		m.Output = override.Output
+	}
+}
+
+// sameModelID compares model IDs ignoring case and Gemini's "models/" prefix
+func sameModelID(a, b string) bool {
+	return strings.EqualFold(strings.TrimPrefix(a, "models/"), strings.TrimPrefix(b, "models/"))
+}
@@ -0,0 +1,540 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"visionflow/database"
+)
+
+const defaultOllamaBaseURL = "http://localhost:11434"
+
+// OllamaClient implements the AIClient interface for a local Ollama server
+type OllamaClient struct {
+	httpClient *http.Client
+	baseURL    string
+	config     database.ModelProvider
+	retry      RetryPolicy
+}
+
+// NewOllamaClient creates a new Ollama client.
+// The API key is optional and only sent when the server sits behind an authenticating proxy.
+func NewOllamaClient(config database.ModelProvider) (*OllamaClient, error) {
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultOllamaBaseURL
+	}
+	// Users often paste the OpenAI compatible endpoint, strip it to reach the native API
+	baseURL = strings.TrimSuffix(baseURL, "/v1")
+	baseURL = strings.TrimSuffix(baseURL, "/api")
+
+	return &OllamaClient{
+		httpClient: newHTTPClient(),
+		baseURL:    baseURL,
+		config:     config,
+		retry:      retryPolicyFor(config),
+	}, nil
+}
+
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	Images    []string         `json:"images,omitempty"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"` // Tool a tool message answers
+}
+
+type ollamaToolCall struct {
+	ID       string `json:"id,omitempty"`
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"` // An object, not a JSON string like OpenAI
+	} `json:"function"`
+}
+
+type ollamaTool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string                 `json:"name"`
+		Description string                 `json:"description,omitempty"`
+		Parameters  map[string]interface{} `json:"parameters"`
+	} `json:"function"`
+}
+
+type ollamaChatRequest struct {
+	Model    string                 `json:"model"`
+	Messages []ollamaMessage        `json:"messages"`
+	Tools    []ollamaTool           `json:"tools,omitempty"`
+	Stream   bool                   `json:"stream"`
+	Format   interface{}            `json:"format,omitempty"` // "json" or a JSON schema
+	Options  map[string]interface{} `json:"options,omitempty"`
+}
+
+type ollamaChatResponse struct {
+	Model           string        `json:"model"`
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+	Error           string        `json:"error,omitempty"`
+}
+
+type ollamaTagsResponse struct {
+	Models []struct {
+		Name       string `json:"name"`
+		Model      string `json:"model"`
+		ModifiedAt string `json:"modified_at"`
+		Details    struct {
+			Family   string   `json:"family"`
+			Families []string `json:"families"`
+		} `json:"details"`
+	} `json:"models"`
+}
+
+func (c *OllamaClient) newRequest(ctx context.Context, method, path string, payload interface{}) (*http.Request, error) {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if payload != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.config.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	}
+	return httpReq, nil
+}
+
+// buildChatRequest maps a TextGenerateRequest onto an Ollama chat request
+func (c *OllamaClient) buildChatRequest(req TextGenerateRequest, stream bool) (*ollamaChatRequest, error) {
+	if req.Model == "" {
+		return nil, errors.New("model is required for Ollama")
+	}
+	if err := req.ResponseFormat.validate(); err != nil {
+		return nil, err
+	}
+	if err := req.validateTools(); err != nil {
+		return nil, err
+	}
+
+	conversation, err := req.conversation()
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []ollamaMessage
+	if req.System != "" {
+		messages = append(messages, ollamaMessage{Role: "system", Content: req.System})
+	}
+	for _, msg := range conversation {
+		if msg.Role == RoleTool {
+			for _, result := range msg.ToolResults {
+				messages = append(messages, ollamaMessage{Role: RoleTool, Content: result.Content, ToolName: result.Name})
+			}
+			continue
+		}
+
+		message := ollamaMessage{
+			Role:    msg.Role,
+			Content: msg.Content,
+		}
+		for _, call := range msg.ToolCalls {
+			toolCall := ollamaToolCall{ID: call.ID}
+			toolCall.Function.Name = call.Name
+			toolCall.Function.Arguments = json.RawMessage(call.Arguments)
+			if len(toolCall.Function.Arguments) == 0 {
+				toolCall.Function.Arguments = json.RawMessage("{}")
+			}
+			message.ToolCalls = append(message.ToolCalls, toolCall)
+		}
+
+		for _, imgPath := range msg.Images {
+			data, err := LoadContent(imgPath)
+			if err != nil {
+				return nil, err
+			}
+			message.Images = append(message.Images, base64.StdEncoding.EncodeToString(data))
+		}
+		messages = append(messages, message)
+	}
+
+	chatReq := &ollamaChatRequest{
+		Model:    req.Model,
+		Messages: messages,
+		Stream:   stream,
+	}
+
+	// Ollama has no tool choice, leaving the tools out is the only way to prevent calls
+	if req.ToolChoice != ToolChoiceNone {
+		for _, tool := range req.Tools {
+			ollamaTool := ollamaTool{Type: "function"}
+			ollamaTool.Function.Name = tool.Name
+			ollamaTool.Function.Description = tool.Description
+			ollamaTool.Function.Parameters = tool.toolParameters()
+			chatReq.Tools = append(chatReq.Tools, ollamaTool)
+		}
+	}
+
+	if format := req.ResponseFormat; format.structured() {
+		chatReq.Format = "json"
+		if len(format.Schema) > 0 {
+			chatReq.Format = format.Schema
+		}
+	}
+
+	resolved, err := resolveOptions(database.ProviderOllama, req.Model, req.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	// The parameter names match Ollama's model options, so they are passed as they are
+	options := map[string]interface{}{}
+	for name, value := range resolved {
+		options[name] = value
+	}
+	if req.Temperature != nil {
+		options["temperature"] = *req.Temperature
+	}
+	if req.MaxTokens != nil {
+		options["num_predict"] = *req.MaxTokens
+	}
+	if len(options) > 0 {
+		chatReq.Options = options
+	}
+
+	return chatReq, nil
+}
+
+// ollamaToolCalls maps the tool calls of a response message, older servers send no IDs
+func ollamaToolCalls(calls []ollamaToolCall, offset int) []ToolCall {
+	var toolCalls []ToolCall
+	for i, call := range calls {
+		toolCall := ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: string(call.Function.Arguments)}
+		if toolCall.ID == "" {
+			toolCall.ID = fmt.Sprintf("call_%d", offset+i)
+		}
+		if toolCall.Arguments == "" || toolCall.Arguments == "null" {
+			toolCall.Arguments = "{}"
+		}
+		toolCalls = append(toolCalls, toolCall)
+	}
+	return toolCalls
+}
+
+func (c *OllamaClient) chat(ctx context.Context, chatReq *ollamaChatRequest) (*ollamaChatResponse, error) {
+	httpReq, err := c.newRequest(ctx, "POST", "/api/chat", chatReq)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("Ollama chat request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Ollama response: %w", err)
+	}
+
+	var chatResp ollamaChatResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil || resp.StatusCode != http.StatusOK {
+		if resp.StatusCode != http.StatusOK {
+			errMsg := chatResp.Error
+			if errMsg == "" {
+				errMsg = string(body)
+			}
+			return nil, fmt.Errorf("Ollama chat request failed: %w", &HTTPStatusError{StatusCode: resp.StatusCode, Body: errMsg})
+		}
+		return nil, fmt.Errorf("failed to decode Ollama response: %w", err)
+	}
+
+	return &chatResp, nil
+}
+
+// GenerateText generates text using Ollama's chat API
+func (c *OllamaClient) GenerateText(ctx context.Context, req TextGenerateRequest) (*TextGenerateResponse, error) {
+	chatReq, err := c.buildChatRequest(req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	chatResp, err := withRetry(ctx, c.retry, func(ctx context.Context) (*ollamaChatResponse, error) {
+		return c.chat(ctx, chatReq)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	model := chatResp.Model
+	if model == "" {
+		model = req.Model
+	}
+
+	result := &TextGenerateResponse{
+		Content:      chatResp.Message.Content,
+		ToolCalls:    ollamaToolCalls(chatResp.Message.ToolCalls, 0),
+		PromptTokens: chatResp.PromptEvalCount,
+		OutputTokens: chatResp.EvalCount,
+		TotalTokens:  chatResp.PromptEvalCount + chatResp.EvalCount,
+		Model:        model,
+	}
+	if err := checkStructuredOutput(req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamText streams text using Ollama's chat API, which answers with one JSON object per line
+func (c *OllamaClient) StreamText(ctx context.Context, req TextGenerateRequest, onDelta func(delta string)) (*TextGenerateResponse, error) {
+	chatReq, err := c.buildChatRequest(req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := withRetry(ctx, c.retry, func(ctx context.Context) (*TextGenerateResponse, error) {
+		httpReq, err := c.newRequest(ctx, "POST", "/api/chat", chatReq)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			return nil, fmt.Errorf("Ollama chat request failed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("Ollama chat request failed: %w", &HTTPStatusError{StatusCode: resp.StatusCode, Body: string(body)})
+		}
+
+		result := &TextGenerateResponse{Model: req.Model}
+		var content strings.Builder
+		decoder := json.NewDecoder(resp.Body)
+		for {
+			var chunk ollamaChatResponse
+			if err := decoder.Decode(&chunk); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				err = fmt.Errorf("failed to decode Ollama stream: %w", err)
+				// Retrying after output was emitted would duplicate it
+				if content.Len() > 0 || len(result.ToolCalls) > 0 {
+					return nil, &permanentError{err}
+				}
+				return nil, err
+			}
+			if chunk.Error != "" {
+				return nil, fmt.Errorf("Ollama chat stream failed: %s", chunk.Error)
+			}
+
+			if chunk.Message.Content != "" {
+				content.WriteString(chunk.Message.Content)
+				onDelta(chunk.Message.Content)
+			}
+			result.ToolCalls = append(result.ToolCalls, ollamaToolCalls(chunk.Message.ToolCalls, len(result.ToolCalls))...)
+
+			// The final object carries the token counts
+			if chunk.Done {
+				if chunk.Model != "" {
+					result.Model = chunk.Model
+				}
+				result.PromptTokens = chunk.PromptEvalCount
+				result.OutputTokens = chunk.EvalCount
+				result.TotalTokens = chunk.PromptEvalCount + chunk.EvalCount
+				break
+			}
+		}
+
+		result.Content = content.String()
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStructuredOutput(req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GenerateImage is not supported by Ollama
+func (c *OllamaClient) GenerateImage(ctx context.Context, req ImageGenerateRequest) (*ImageGenerateResponse, error) {
+	return nil, errors.New("image generation is not supported by Ollama")
+}
+
+// GenerateAudio is not supported by Ollama
+func (c *OllamaClient) GenerateAudio(ctx context.Context, req AudioGenerateRequest) (*AudioGenerateResponse, error) {
+	return nil, errors.New("audio generation is not supported by Ollama")
+}
+
+// Transcribe is not supported by Ollama
+func (c *OllamaClient) Transcribe(ctx context.Context, req TranscribeRequest) (*TranscribeResponse, error) {
+	return nil, errors.New("transcription is not supported by Ollama")
+}
+
+type ollamaEmbedRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+type ollamaEmbedResponse struct {
+	Model           string      `json:"model"`
+	Embeddings      [][]float32 `json:"embeddings"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+	Error           string      `json:"error,omitempty"`
+}
+
+// Embed embeds texts using Ollama's embed API with a locally pulled embedding model, e.g. nomic-embed-text
+func (c *OllamaClient) Embed(ctx context.Context, req EmbedRequest) (*EmbedResponse, error) {
+	if req.Model == "" {
+		return nil, errors.New("model is required for Ollama")
+	}
+
+	embedReq := ollamaEmbedRequest{Model: req.Model, Input: req.Inputs, Dimensions: req.Dimensions}
+	embedResp, err := withRetry(ctx, c.retry, func(ctx context.Context) (*ollamaEmbedResponse, error) {
+		httpReq, err := c.newRequest(ctx, "POST", "/api/embed", embedReq)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			return nil, fmt.Errorf("Ollama embed request failed: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Ollama response: %w", err)
+		}
+
+		var embedResp ollamaEmbedResponse
+		if err := json.Unmarshal(body, &embedResp); err != nil || resp.StatusCode != http.StatusOK {
+			if resp.StatusCode != http.StatusOK {
+				errMsg := embedResp.Error
+				if errMsg == "" {
+					errMsg = string(body)
+				}
+				return nil, fmt.Errorf("Ollama embed request failed: %w", &HTTPStatusError{StatusCode: resp.StatusCode, Body: errMsg})
+			}
+			return nil, fmt.Errorf("failed to decode Ollama response: %w", err)
+		}
+		return &embedResp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(embedResp.Embeddings) != len(req.Inputs) {
+		return nil, fmt.Errorf("Ollama returned %d embeddings for %d inputs", len(embedResp.Embeddings), len(req.Inputs))
+	}
+
+	model := embedResp.Model
+	if model == "" {
+		model = req.Model
+	}
+	return &EmbedResponse{
+		Embeddings:   embedResp.Embeddings,
+		PromptTokens: embedResp.PromptEvalCount,
+		Model:        model,
+	}, nil
+}
+
+// GenerateVideo is not supported by Ollama
+func (c *OllamaClient) GenerateVideo(ctx context.Context, req VideoGenerateRequest) (*VideoGenerateResponse, error) {
+	return nil, errors.New("video generation is not supported by Ollama")
+}
+
+// ListModels lists the models pulled into the local Ollama server
+func (c *OllamaClient) ListModels(ctx context.Context) ([]Model, error) {
+	httpReq, err := c.newRequest(ctx, "GET", "/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Ollama models: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to list Ollama models: status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tags ollamaTagsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("failed to decode Ollama models: %w", err)
+	}
+
+	var models []Model
+	for _, m := range tags.Models {
+		id := m.Name
+		if id == "" {
+			id = m.Model
+		}
+
+		input, output := GetModelCapabilities(id)
+		if len(input) == 0 {
+			// Local tags look like "llama3.2:3b", models.dev only knows the base name
+			input, output = GetModelCapabilities(strings.SplitN(id, ":", 2)[0])
+		}
+		if len(input) == 0 {
+			families := m.Details.Families
+			if len(families) == 0 && m.Details.Family != "" {
+				families = []string{m.Details.Family}
+			}
+			input, output = inferOllamaCapabilities(families)
+		}
+
+		models = append(models, Model{
+			ID:           id,
+			Object:       "model",
+			ProviderName: c.config.Name,
+			ProviderType: string(c.config.Type),
+			Input:        input,
+			Output:       output,
+		})
+	}
+
+	return annotateModels(c.config, models), nil
+}
+
+// ollamaVisionFamilies lists model families whose weights ship with a vision projector
+var ollamaVisionFamilies = []string{"clip", "mllama", "llava", "qwen2vl", "qwen25vl", "gemma3", "minicpmv", "moondream"}
+
+// inferOllamaCapabilities guesses the modalities of a local model from its families
+// when models.dev has no entry for it.
+func inferOllamaCapabilities(families []string) (input, output []string) {
+	input = []string{"text"}
+	output = []string{"text"}
+	for _, family := range families {
+		for _, vision := range ollamaVisionFamilies {
+			if strings.EqualFold(family, vision) {
+				return []string{"text", "image"}, output
+			}
+		}
+	}
+	return input, output
+}
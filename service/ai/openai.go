@@ -11,7 +11,9 @@ import (
 	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,8 +24,10 @@ import (
 
 // OpenAIClient implements the AIClient interface for OpenAI
 type OpenAIClient struct {
-	client *openai.Client
-	config database.ModelProvider
+	client     *openai.Client
+	httpClient *http.Client
+	config     database.ModelProvider
+	retry      RetryPolicy
 }
 
 // NewOpenAIClient creates a new OpenAI client
@@ -36,81 +40,232 @@ func NewOpenAIClient(config database.ModelProvider) (*OpenAIClient, error) {
 	if config.BaseURL != "" {
 		clientConfig.BaseURL = config.BaseURL
 	}
+	httpClient := newHTTPClient()
+	clientConfig.HTTPClient = httpClient
 
 	return &OpenAIClient{
-		client: openai.NewClientWithConfig(clientConfig),
-		config: config,
+		client:     openai.NewClientWithConfig(clientConfig),
+		httpClient: httpClient,
+		config:     config,
+		retry:      retryPolicyFor(config),
 	}, nil
 }
 
-// GenerateText generates text using OpenAI's chat completion API
-func (c *OpenAIClient) GenerateText(ctx context.Context, req TextGenerateRequest) (*TextGenerateResponse, error) {
-	if req.Model == "" {
-		req.Model = openai.GPT4o
+// buildChatRequest maps a TextGenerateRequest onto an OpenAI chat completion request
+func (c *OpenAIClient) buildChatRequest(req TextGenerateRequest) (openai.ChatCompletionRequest, error) {
+	if err := req.ResponseFormat.validate(); err != nil {
+		return openai.ChatCompletionRequest{}, err
+	}
+	if err := req.validateTools(); err != nil {
+		return openai.ChatCompletionRequest{}, err
+	}
+	options, err := resolveOptions(database.ProviderOpenAI, req.Model, req.Options)
+	if err != nil {
+		return openai.ChatCompletionRequest{}, err
+	}
+
+	conversation, err := req.conversation()
+	if err != nil {
+		return openai.ChatCompletionRequest{}, err
 	}
 
 	var messages []openai.ChatCompletionMessage
+	if req.System != "" {
+		messages = append(messages, openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: req.System,
+		})
+	}
+	for _, msg := range conversation {
+		// Every tool result is its own message referencing the call it answers
+		if msg.Role == RoleTool {
+			for _, result := range msg.ToolResults {
+				messages = append(messages, openai.ChatCompletionMessage{
+					Role:       openai.ChatMessageRoleTool,
+					Content:    result.Content,
+					ToolCallID: result.CallID,
+				})
+			}
+			continue
+		}
 
-	if len(req.Images) > 0 {
-		parts := []openai.ChatMessagePart{
-			{
-				Type: openai.ChatMessagePartTypeText,
-				Text: req.Prompt,
-			},
+		message, err := openAIMessage(msg)
+		if err != nil {
+			return openai.ChatCompletionRequest{}, err
 		}
+		messages = append(messages, message)
+	}
+
+	chatReq := openai.ChatCompletionRequest{
+		Model:    req.Model,
+		Messages: messages,
+	}
+
+	if req.Temperature != nil {
+		chatReq.Temperature = float32(*req.Temperature)
+	}
+
+	if req.MaxTokens != nil {
+		chatReq.MaxTokens = *req.MaxTokens
+	}
+
+	if topP, ok := options.float("top_p"); ok {
+		chatReq.TopP = float32(topP)
+	}
+	if penalty, ok := options.float("frequency_penalty"); ok {
+		chatReq.FrequencyPenalty = float32(penalty)
+	}
+	if penalty, ok := options.float("presence_penalty"); ok {
+		chatReq.PresencePenalty = float32(penalty)
+	}
+	if seed, ok := options.int("seed"); ok {
+		chatReq.Seed = &seed
+	}
+	if effort, ok := options.string("reasoning_effort"); ok {
+		chatReq.ReasoningEffort = effort
+	}
 
-		for _, imgPath := range req.Images {
-			data, err := LoadContent(imgPath)
+	if format := req.ResponseFormat; format.structured() {
+		chatReq.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONObject,
+		}
+		// json_object mode is refused unless the word "json" appears somewhere in the messages
+		if len(format.Schema) == 0 && !mentionsJSON(chatReq.Messages) {
+			chatReq.Messages = append([]openai.ChatCompletionMessage{{
+				Role:    openai.ChatMessageRoleSystem,
+				Content: "Respond with a single JSON object.",
+			}}, chatReq.Messages...)
+		}
+		if len(format.Schema) > 0 {
+			schema, err := json.Marshal(format.Schema)
 			if err != nil {
-				return nil, err
+				return openai.ChatCompletionRequest{}, fmt.Errorf("invalid response schema: %w", err)
 			}
+			chatReq.ResponseFormat = &openai.ChatCompletionResponseFormat{
+				Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+				JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+					Name:   format.schemaName(),
+					Schema: json.RawMessage(schema),
+					Strict: format.Strict,
+				},
+			}
+		}
+	}
 
-			ext := filepath.Ext(imgPath)
-			mimeType := mime.TypeByExtension(ext)
-			if mimeType == "" {
-				mimeType = http.DetectContentType(data)
+	for _, tool := range req.Tools {
+		chatReq.Tools = append(chatReq.Tools, openai.Tool{
+			Type: openai.ToolTypeFunction,
+			Function: &openai.FunctionDefinition{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.toolParameters(),
+			},
+		})
+	}
+	if len(chatReq.Tools) > 0 {
+		switch req.ToolChoice {
+		case "":
+		case ToolChoiceAuto, ToolChoiceNone, ToolChoiceRequired:
+			chatReq.ToolChoice = req.ToolChoice
+		default:
+			chatReq.ToolChoice = openai.ToolChoice{
+				Type:     openai.ToolTypeFunction,
+				Function: openai.ToolFunction{Name: req.ToolChoice},
 			}
+		}
+	}
+
+	return chatReq, nil
+}
 
-			b64Data := base64.StdEncoding.EncodeToString(data)
-			imgURL := fmt.Sprintf("data:%s;base64,%s", mimeType, b64Data)
+// mentionsJSON reports whether any message text contains the word "json" in any case
+func mentionsJSON(messages []openai.ChatCompletionMessage) bool {
+	for _, message := range messages {
+		if strings.Contains(strings.ToLower(message.Content), "json") {
+			return true
+		}
+		for _, part := range message.MultiContent {
+			if strings.Contains(strings.ToLower(part.Text), "json") {
+				return true
+			}
+		}
+	}
+	return false
+}
 
-			parts = append(parts, openai.ChatMessagePart{
-				Type: openai.ChatMessagePartTypeImageURL,
-				ImageURL: &openai.ChatMessageImageURL{
-					URL: imgURL,
+// openAIMessage maps a conversation turn onto a chat message, images are sent inline as data URLs
+func openAIMessage(msg Message) (openai.ChatCompletionMessage, error) {
+	role := openai.ChatMessageRoleUser
+	if msg.Role == RoleAssistant {
+		role = openai.ChatMessageRoleAssistant
+	}
+
+	if len(msg.ToolCalls) > 0 {
+		message := openai.ChatCompletionMessage{Role: role, Content: msg.Content}
+		for _, call := range msg.ToolCalls {
+			message.ToolCalls = append(message.ToolCalls, openai.ToolCall{
+				ID:   call.ID,
+				Type: openai.ToolTypeFunction,
+				Function: openai.FunctionCall{
+					Name:      call.Name,
+					Arguments: call.Arguments,
 				},
 			})
 		}
+		return message, nil
+	}
 
-		messages = []openai.ChatCompletionMessage{
-			{
-				Role:         openai.ChatMessageRoleUser,
-				MultiContent: parts,
-			},
+	if len(msg.Images) == 0 {
+		return openai.ChatCompletionMessage{Role: role, Content: msg.Content}, nil
+	}
+
+	parts := []openai.ChatMessagePart{
+		{
+			Type: openai.ChatMessagePartTypeText,
+			Text: msg.Content,
+		},
+	}
+
+	for _, imgPath := range msg.Images {
+		data, err := LoadContent(imgPath)
+		if err != nil {
+			return openai.ChatCompletionMessage{}, err
 		}
-	} else {
-		messages = []openai.ChatCompletionMessage{
-			{
-				Role:    openai.ChatMessageRoleUser,
-				Content: req.Prompt,
-			},
+
+		ext := filepath.Ext(imgPath)
+		mimeType := mime.TypeByExtension(ext)
+		if mimeType == "" {
+			mimeType = http.DetectContentType(data)
 		}
-	}
 
-	chatReq := openai.ChatCompletionRequest{
-		Model:    req.Model,
-		Messages: messages,
+		b64Data := base64.StdEncoding.EncodeToString(data)
+		imgURL := fmt.Sprintf("data:%s;base64,%s", mimeType, b64Data)
+
+		parts = append(parts, openai.ChatMessagePart{
+			Type: openai.ChatMessagePartTypeImageURL,
+			ImageURL: &openai.ChatMessageImageURL{
+				URL: imgURL,
+			},
+		})
 	}
 
-	if req.Temperature != nil {
-		chatReq.Temperature = float32(*req.Temperature)
+	return openai.ChatCompletionMessage{Role: role, MultiContent: parts}, nil
+}
+
+// GenerateText generates text using OpenAI's chat completion API
+func (c *OpenAIClient) GenerateText(ctx context.Context, req TextGenerateRequest) (*TextGenerateResponse, error) {
+	if req.Model == "" {
+		req.Model = openai.GPT4o
 	}
 
-	if req.MaxTokens != nil {
-		chatReq.MaxTokens = *req.MaxTokens
+	chatReq, err := c.buildChatRequest(req)
+	if err != nil {
+		return nil, err
 	}
 
-	resp, err := c.client.CreateChatCompletion(ctx, chatReq)
+	resp, err := withRetry(ctx, c.retry, func(ctx context.Context) (openai.ChatCompletionResponse, error) {
+		return c.client.CreateChatCompletion(ctx, chatReq)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("OpenAI chat completion failed: %w", err)
 	}
@@ -119,25 +274,142 @@ func (c *OpenAIClient) GenerateText(ctx context.Context, req TextGenerateRequest
 		return nil, errors.New("no response from OpenAI")
 	}
 
-	return &TextGenerateResponse{
+	result := &TextGenerateResponse{
 		Content:      resp.Choices[0].Message.Content,
 		PromptTokens: resp.Usage.PromptTokens,
 		OutputTokens: resp.Usage.CompletionTokens,
 		TotalTokens:  resp.Usage.TotalTokens,
 		Model:        resp.Model,
-	}, nil
+	}
+	for _, call := range resp.Choices[0].Message.ToolCalls {
+		result.ToolCalls = append(result.ToolCalls, ToolCall{
+			ID:        call.ID,
+			Name:      call.Function.Name,
+			Arguments: call.Function.Arguments,
+		})
+	}
+	if err := checkStructuredOutput(req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamText streams text using OpenAI's chat completion API, calling onDelta for every content chunk
+func (c *OpenAIClient) StreamText(ctx context.Context, req TextGenerateRequest, onDelta func(delta string)) (*TextGenerateResponse, error) {
+	if req.Model == "" {
+		req.Model = openai.GPT4o
+	}
+
+	chatReq, err := c.buildChatRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	chatReq.Stream = true
+	chatReq.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+
+	result, err := withRetry(ctx, c.retry, func(ctx context.Context) (*TextGenerateResponse, error) {
+		stream, err := c.client.CreateChatCompletionStream(ctx, chatReq)
+		if err != nil {
+			return nil, fmt.Errorf("OpenAI chat completion stream failed: %w", err)
+		}
+		defer stream.Close()
+
+		result := &TextGenerateResponse{Model: req.Model}
+		var content strings.Builder
+		// Tool calls arrive in fragments, the first one of each call carries its ID and name
+		var calls []ToolCall
+		for {
+			chunk, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				err = fmt.Errorf("OpenAI chat completion stream failed: %w", err)
+				// Retrying after output was emitted would duplicate it
+				if content.Len() > 0 || len(calls) > 0 {
+					return nil, &permanentError{err}
+				}
+				return nil, err
+			}
+
+			if chunk.Model != "" {
+				result.Model = chunk.Model
+			}
+			// Usage only arrives on the last chunk because include_usage is set
+			if chunk.Usage != nil {
+				result.PromptTokens = chunk.Usage.PromptTokens
+				result.OutputTokens = chunk.Usage.CompletionTokens
+				result.TotalTokens = chunk.Usage.TotalTokens
+			}
+			if len(chunk.Choices) == 0 {
+				continue
+			}
+			if delta := chunk.Choices[0].Delta.Content; delta != "" {
+				content.WriteString(delta)
+				onDelta(delta)
+			}
+			for _, fragment := range chunk.Choices[0].Delta.ToolCalls {
+				index := len(calls) - 1
+				if fragment.Index != nil {
+					index = *fragment.Index
+				}
+				if index < 0 {
+					index = 0
+				}
+				for index >= len(calls) {
+					calls = append(calls, ToolCall{})
+				}
+				if fragment.ID != "" {
+					calls[index].ID = fragment.ID
+				}
+				if fragment.Function.Name != "" {
+					calls[index].Name = fragment.Function.Name
+				}
+				calls[index].Arguments += fragment.Function.Arguments
+			}
+		}
+
+		result.Content = content.String()
+		result.ToolCalls = calls
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStructuredOutput(req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // GenerateImage generates an image using OpenAI's DALL-E API
 func (c *OpenAIClient) GenerateImage(ctx context.Context, req ImageGenerateRequest) (*ImageGenerateResponse, error) {
+	if len(req.Images) > 0 {
+		return c.editImage(ctx, req)
+	}
+	if req.Mask != "" {
+		return nil, errors.New("a mask needs an input image to edit")
+	}
+
 	if req.Model == "" {
 		req.Model = openai.CreateImageModelDallE3
 	}
 
+	n := max(req.N, 1)
+	if n > 1 && req.Model == openai.CreateImageModelDallE3 {
+		return nil, errors.New("dall-e-3 generates one image per request, use gpt-image-1 or dall-e-2 for several")
+	}
+	options, err := resolveOptions(database.ProviderOpenAI, req.Model, req.Options)
+	if err != nil {
+		return nil, err
+	}
+	req.applyImageOptions(options)
+
 	imageReq := openai.ImageRequest{
 		Prompt: req.Prompt,
 		Model:  req.Model,
-		N:      1,
+		N:      n,
 	}
 
 	if req.Size != "" {
@@ -154,10 +426,18 @@ func (c *OpenAIClient) GenerateImage(ctx context.Context, req ImageGenerateReque
 		imageReq.Style = req.Style
 	}
 
-	// Default to b64_json for easiest handling in frontend
-	imageReq.ResponseFormat = openai.CreateImageResponseFormatB64JSON
+	imageReq.Background, _ = options.string("background")
+	imageReq.OutputFormat, _ = options.string("output_format")
+	imageReq.Moderation, _ = options.string("moderation")
 
-	resp, err := c.client.CreateImage(ctx, imageReq)
+	// Default to b64_json for easiest handling in frontend, gpt-image models always answer with base64 and reject the field
+	if !strings.HasPrefix(req.Model, "gpt-image") {
+		imageReq.ResponseFormat = openai.CreateImageResponseFormatB64JSON
+	}
+
+	resp, err := withCreateRetry(ctx, c.retry, func(ctx context.Context) (openai.ImageResponse, error) {
+		return c.client.CreateImage(ctx, imageReq)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("OpenAI image generation failed: %w", err)
 	}
@@ -167,19 +447,171 @@ func (c *OpenAIClient) GenerateImage(ctx context.Context, req ImageGenerateReque
 	}
 
 	return &ImageGenerateResponse{
-		URL:           resp.Data[0].URL,
-		B64JSON:       resp.Data[0].B64JSON,
-		RevisedPrompt: resp.Data[0].RevisedPrompt,
-		Model:         req.Model, // API doesn't return model in response struct usually
+		Images: openAIImages(resp),
+		Model:  req.Model, // API doesn't return model in response struct usually
+	}, nil
+}
+
+// openAIImages converts the images of an OpenAI image response
+func openAIImages(resp openai.ImageResponse) []GeneratedImage {
+	images := make([]GeneratedImage, 0, len(resp.Data))
+	for _, data := range resp.Data {
+		images = append(images, GeneratedImage{
+			URL:           data.URL,
+			B64JSON:       data.B64JSON,
+			RevisedPrompt: data.RevisedPrompt,
+		})
+	}
+	return images
+}
+
+// editImage modifies the request's input images through the /images/edits endpoint. The mask is a
+// PNG of the same size whose fully transparent pixels mark the region to repaint.
+// The request is built by hand because go-openai's CreateEditImage does not send the model.
+func (c *OpenAIClient) editImage(ctx context.Context, req ImageGenerateRequest) (*ImageGenerateResponse, error) {
+	if req.Model == "" {
+		req.Model = "gpt-image-1"
+	}
+	gptImage := strings.HasPrefix(req.Model, "gpt-image")
+	switch {
+	case req.Model == openai.CreateImageModelDallE3:
+		return nil, errors.New("dall-e-3 does not support image editing, use gpt-image-1 or dall-e-2")
+	case !gptImage && len(req.Images) > 1:
+		return nil, fmt.Errorf("%s edits a single image, got %d", req.Model, len(req.Images))
+	}
+	options, err := resolveOptions(database.ProviderOpenAI, req.Model, req.Options)
+	if err != nil {
+		return nil, err
+	}
+	req.applyImageOptions(options)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	fields := map[string]string{
+		"model":  req.Model,
+		"prompt": req.Prompt,
+		"n":      strconv.Itoa(max(req.N, 1)),
+		"size":   req.Size,
+	}
+	if gptImage {
+		// gpt-image models always answer with base64 and reject response_format
+		fields["quality"] = req.Quality
+		fields["background"], _ = options.string("background")
+		fields["output_format"], _ = options.string("output_format")
+		fields["moderation"], _ = options.string("moderation")
+	} else {
+		fields["response_format"] = openai.CreateImageResponseFormatB64JSON
+	}
+	for _, name := range []string{"model", "prompt", "n", "size", "quality", "background", "output_format", "moderation", "response_format"} {
+		if fields[name] == "" {
+			continue
+		}
+		if err := writer.WriteField(name, fields[name]); err != nil {
+			return nil, fmt.Errorf("failed to write %s field: %w", name, err)
+		}
+	}
+
+	imageField := "image"
+	if gptImage {
+		imageField = "image[]"
+	}
+	for _, image := range req.Images {
+		if err := writeFormImage(writer, imageField, image); err != nil {
+			return nil, err
+		}
+	}
+	if req.Mask != "" {
+		if err := writeFormImage(writer, "mask", req.Mask); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+
+	reqURL := fmt.Sprintf("%s/images/edits", c.apiBaseURL())
+	payload := body.Bytes()
+
+	resp, err := withCreateRetry(ctx, c.retry, func(ctx context.Context) (openai.ImageResponse, error) {
+		var imageResp openai.ImageResponse
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewReader(payload))
+		if err != nil {
+			return imageResp, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		httpReq.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+		httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			return imageResp, fmt.Errorf("failed to send image edit request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			return imageResp, fmt.Errorf("image edit request failed: %w", &HTTPStatusError{StatusCode: resp.StatusCode, Body: string(body)})
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&imageResp); err != nil {
+			return imageResp, fmt.Errorf("failed to decode image edit response: %w", err)
+		}
+		return imageResp, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI image edit failed: %w", err)
+	}
+
+	if len(resp.Data) == 0 {
+		return nil, errors.New("no image generated from OpenAI")
+	}
+
+	return &ImageGenerateResponse{
+		Images: openAIImages(resp),
+		Model:  req.Model,
 	}, nil
 }
 
+// writeFormImage adds an image file part, with its real content type since the edits endpoint
+// rejects application/octet-stream
+func writeFormImage(writer *multipart.Writer, field, pathOrURL string) error {
+	data, err := LoadContent(pathOrURL)
+	if err != nil {
+		return err
+	}
+
+	name := ContentName(pathOrURL)
+	contentType := mime.TypeByExtension(filepath.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, field, name))
+	header.Set("Content-Type", contentType)
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return fmt.Errorf("failed to copy file content: %w", err)
+	}
+	return nil
+}
+
 // GenerateAudio generates audio using OpenAI's TTS API
 func (c *OpenAIClient) GenerateAudio(ctx context.Context, req AudioGenerateRequest) (*AudioGenerateResponse, error) {
 	if req.Model == "" {
 		req.Model = string(openai.TTSModel1)
 	}
 
+	options, err := resolveOptions(database.ProviderOpenAI, req.Model, req.Options)
+	if err != nil {
+		return nil, err
+	}
+
 	voice := openai.VoiceAlloy
 	if req.Voice != "" {
 		voice = openai.SpeechVoice(req.Voice)
@@ -190,20 +622,27 @@ func (c *OpenAIClient) GenerateAudio(ctx context.Context, req AudioGenerateReque
 		Input: req.Prompt,
 		Voice: voice,
 	}
+	audioReq.Instructions, _ = options.string("instructions")
 
 	if req.Speed != nil {
 		audioReq.Speed = *req.Speed
 	}
 
-	resp, err := c.client.CreateSpeech(ctx, audioReq)
-	if err != nil {
-		return nil, fmt.Errorf("OpenAI audio generation failed: %w", err)
-	}
-	defer resp.Close()
+	data, err := withRetry(ctx, c.retry, func(ctx context.Context) ([]byte, error) {
+		resp, err := c.client.CreateSpeech(ctx, audioReq)
+		if err != nil {
+			return nil, fmt.Errorf("OpenAI audio generation failed: %w", err)
+		}
+		defer resp.Close()
 
-	data, err := io.ReadAll(resp)
+		data, err := io.ReadAll(resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audio data: %w", err)
+		}
+		return data, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read audio data: %w", err)
+		return nil, err
 	}
 
 	return &AudioGenerateResponse{
@@ -212,34 +651,160 @@ func (c *OpenAIClient) GenerateAudio(ctx context.Context, req AudioGenerateReque
 	}, nil
 }
 
+// Transcribe transcribes audio or video using OpenAI's transcription API.
+// whisper-1 returns timed segments, the gpt-4o transcribe models only return plain text.
+func (c *OpenAIClient) Transcribe(ctx context.Context, req TranscribeRequest) (*TranscribeResponse, error) {
+	if req.Model == "" {
+		req.Model = openai.Whisper1
+	}
+	if _, err := resolveOptions(database.ProviderOpenAI, req.Model, req.Options); err != nil {
+		return nil, err
+	}
+
+	data, err := LoadContent(req.Media)
+	if err != nil {
+		return nil, err
+	}
+
+	audioReq := openai.AudioRequest{
+		Model:    req.Model,
+		FilePath: ContentName(req.Media), // Only the name is sent, the extension tells the API the format
+		Prompt:   req.Prompt,
+		Language: req.Language,
+		Format:   openai.AudioResponseFormatJSON,
+	}
+	verbose := strings.HasPrefix(req.Model, "whisper")
+	if verbose {
+		audioReq.Format = openai.AudioResponseFormatVerboseJSON
+		audioReq.TimestampGranularities = []openai.TranscriptionTimestampGranularity{openai.TranscriptionTimestampGranularitySegment}
+	}
+
+	resp, err := withRetry(ctx, c.retry, func(ctx context.Context) (openai.AudioResponse, error) {
+		// The reader is consumed by every attempt
+		audioReq.Reader = bytes.NewReader(data)
+		return c.client.CreateTranscription(ctx, audioReq)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI transcription failed: %w", err)
+	}
+
+	result := &TranscribeResponse{
+		Text:     strings.TrimSpace(resp.Text),
+		Language: resp.Language,
+		Duration: resp.Duration,
+		Model:    req.Model,
+	}
+	for _, segment := range resp.Segments {
+		result.Segments = append(result.Segments, TranscriptSegment{
+			Start: segment.Start,
+			End:   segment.End,
+			Text:  strings.TrimSpace(segment.Text),
+		})
+	}
+	return result, nil
+}
+
+// Embed embeds texts using OpenAI's embeddings API
+func (c *OpenAIClient) Embed(ctx context.Context, req EmbedRequest) (*EmbedResponse, error) {
+	if req.Model == "" {
+		req.Model = string(openai.SmallEmbedding3)
+	}
+
+	embedReq := openai.EmbeddingRequest{
+		Input:      req.Inputs,
+		Model:      openai.EmbeddingModel(req.Model),
+		Dimensions: req.Dimensions,
+	}
+
+	resp, err := withRetry(ctx, c.retry, func(ctx context.Context) (openai.EmbeddingResponse, error) {
+		return c.client.CreateEmbeddings(ctx, embedReq)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("OpenAI embeddings failed: %w", err)
+	}
+	if len(resp.Data) != len(req.Inputs) {
+		return nil, fmt.Errorf("OpenAI returned %d embeddings for %d inputs", len(resp.Data), len(req.Inputs))
+	}
+
+	result := &EmbedResponse{
+		Embeddings:   make([][]float32, len(req.Inputs)),
+		PromptTokens: resp.Usage.PromptTokens,
+		Model:        string(resp.Model),
+	}
+	for _, data := range resp.Data {
+		if data.Index < 0 || data.Index >= len(result.Embeddings) {
+			return nil, fmt.Errorf("OpenAI returned an embedding for unknown input %d", data.Index)
+		}
+		result.Embeddings[data.Index] = data.Embedding
+	}
+	if result.Model == "" {
+		result.Model = req.Model
+	}
+	return result, nil
+}
+
 // GenerateVideo generates a video using OpenAI's video generation API (Sora)
 func (c *OpenAIClient) GenerateVideo(ctx context.Context, req VideoGenerateRequest) (*VideoGenerateResponse, error) {
 	if req.Model == "" {
 		req.Model = "sora-2" // Default to a known model if unspecified
 	}
 
+	jobID, err := c.StartVideoJob(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.WaitVideoJob(ctx, jobID, req)
+}
+
+func (c *OpenAIClient) apiBaseURL() string {
+	if c.config.BaseURL != "" {
+		return c.config.BaseURL
+	}
+	return "https://api.openai.com/v1"
+}
+
+// StartVideoJob creates a Sora video job and returns its ID without waiting for the result
+func (c *OpenAIClient) StartVideoJob(ctx context.Context, req VideoGenerateRequest) (string, error) {
+	if req.Model == "" {
+		req.Model = "sora-2"
+	}
+	if req.N > 1 {
+		return "", fmt.Errorf("%s generates one video per request", req.Model)
+	}
+	options, err := resolveOptions(database.ProviderOpenAI, req.Model, req.Options)
+	if err != nil {
+		return "", err
+	}
+	if seconds, ok := options.int("seconds"); ok && req.Duration == "" {
+		req.Duration = strconv.Itoa(seconds)
+	}
+	if size, ok := options.string("size"); ok && req.Resolution == "" {
+		req.Resolution = size
+	}
+
 	// Use multipart/form-data
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 
 	// Add fields
 	if err := writer.WriteField("model", req.Model); err != nil {
-		return nil, fmt.Errorf("failed to write model field: %w", err)
+		return "", fmt.Errorf("failed to write model field: %w", err)
 	}
 	if err := writer.WriteField("prompt", req.Prompt); err != nil {
-		return nil, fmt.Errorf("failed to write prompt field: %w", err)
+		return "", fmt.Errorf("failed to write prompt field: %w", err)
 	}
 
 	if req.Resolution != "" {
 		if err := writer.WriteField("size", req.Resolution); err != nil {
-			return nil, fmt.Errorf("failed to write size field: %w", err)
+			return "", fmt.Errorf("failed to write size field: %w", err)
 		}
 	}
 
 	if req.Duration != "" {
 		dur := strings.TrimSuffix(req.Duration, "s")
 		if err := writer.WriteField("seconds", dur); err != nil {
-			return nil, fmt.Errorf("failed to write seconds field: %w", err)
+			return "", fmt.Errorf("failed to write seconds field: %w", err)
 		}
 	}
 
@@ -248,144 +813,187 @@ func (c *OpenAIClient) GenerateVideo(ctx context.Context, req VideoGenerateReque
 		imagePath := req.Images[0]
 		data, err := LoadContent(imagePath)
 		if err != nil {
-			return nil, err
+			return "", err
 		}
 
 		part, err := writer.CreateFormFile("input_reference", filepath.Base(imagePath))
 		if err != nil {
-			return nil, fmt.Errorf("failed to create form file: %w", err)
+			return "", fmt.Errorf("failed to create form file: %w", err)
 		}
 		if _, err := io.Copy(part, bytes.NewReader(data)); err != nil {
-			return nil, fmt.Errorf("failed to copy file content: %w", err)
+			return "", fmt.Errorf("failed to copy file content: %w", err)
 		}
 	}
 
 	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+		return "", fmt.Errorf("failed to close multipart writer: %w", err)
 	}
 
-	baseURL := "https://api.openai.com/v1"
-	if c.config.BaseURL != "" {
-		baseURL = c.config.BaseURL
-	}
+	reqURL := fmt.Sprintf("%s/videos", c.apiBaseURL())
+	payload := body.Bytes()
 
-	// 1. Create Video Generation Job
-	reqURL := fmt.Sprintf("%s/videos", baseURL)
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", reqURL, body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	type jobResponse struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
 	}
+	jobResp, err := withCreateRetry(ctx, c.retry, func(ctx context.Context) (jobResponse, error) {
+		var jobResp jobResponse
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewReader(payload))
+		if err != nil {
+			return jobResp, fmt.Errorf("failed to create request: %w", err)
+		}
 
-	httpReq.Header.Set("Authorization", "Bearer "+c.config.APIKey)
-	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+		httpReq.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+		httpReq.Header.Set("Content-Type", writer.FormDataContentType())
 
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+		resp, err := c.httpClient.Do(httpReq)
+		if err != nil {
+			return jobResp, fmt.Errorf("failed to send video generation request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(resp.Body)
+			return jobResp, fmt.Errorf("video generation request failed: %w", &HTTPStatusError{StatusCode: resp.StatusCode, Body: string(body)})
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(&jobResp); err != nil {
+			return jobResp, fmt.Errorf("failed to decode job response: %w", err)
+		}
+		return jobResp, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to send video generation request: %w", err)
+		return "", err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("video generation request failed with status %d: %s", resp.StatusCode, string(body))
+	if jobResp.ID == "" {
+		return "", errors.New("video generation response did not include a job id")
 	}
 
-	var jobResp struct {
-		ID     string `json:"id"`
-		Status string `json:"status"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&jobResp); err != nil {
-		return nil, fmt.Errorf("failed to decode job response: %w", err)
+	return jobResp.ID, nil
+}
+
+// WaitVideoJob polls a Sora video job until it finishes and downloads the result.
+// It can be called for a job started in a previous session.
+func (c *OpenAIClient) WaitVideoJob(ctx context.Context, jobID string, req VideoGenerateRequest) (*VideoGenerateResponse, error) {
+	if req.Model == "" {
+		req.Model = "sora-2"
 	}
 
-	// 2. Poll for Completion
+	baseURL := c.apiBaseURL()
+
+	// Poll for Completion
 	ticker := time.NewTicker(2 * time.Second)
 	defer ticker.Stop()
 
+Poll:
 	for {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		case <-ticker.C:
-			statusURL := fmt.Sprintf("%s/videos/%s", baseURL, jobResp.ID)
-			statusReq, err := http.NewRequestWithContext(ctx, "GET", statusURL, nil)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create status request: %w", err)
-			}
-			statusReq.Header.Set("Authorization", "Bearer "+c.config.APIKey)
-
-			statusResp, err := client.Do(statusReq)
+			// A transient failure of a single poll must not throw away the whole job
+			statusObj, err := withRetry(ctx, c.retry, func(ctx context.Context) (*openAIVideoStatus, error) {
+				return c.getVideoStatus(ctx, baseURL, jobID)
+			})
 			if err != nil {
-				return nil, fmt.Errorf("failed to check video status: %w", err)
-			}
-
-			var statusObj struct {
-				Status string `json:"status"`
-				Error  *struct {
-					Message string `json:"message"`
-				} `json:"error"`
-			}
-
-			// Decode and close explicitly inside loop to avoid leak
-			body, _ := io.ReadAll(statusResp.Body)
-			statusResp.Body.Close()
-
-			if statusResp.StatusCode != http.StatusOK {
-				return nil, fmt.Errorf("status check failed: %d %s", statusResp.StatusCode, string(body))
+				return nil, err
 			}
 
-			if err := json.Unmarshal(body, &statusObj); err != nil {
-				return nil, fmt.Errorf("failed to decode status response: %w", err)
-			}
+			req.reportProgress(statusObj.Status, statusObj.Progress)
 
 			switch statusObj.Status {
 			case "completed":
-				goto Download
+				break Poll
 			case "failed":
 				errMsg := "unknown error"
 				if statusObj.Error != nil {
 					errMsg = statusObj.Error.Message
 				}
-				return nil, fmt.Errorf("video generation failed: %s", errMsg)
+				return nil, fmt.Errorf("%w: %s", ErrVideoJobFailed, errMsg)
 			}
 			// Continue polling if queued or in_progress
 		}
 	}
 
-Download:
-	// 3. Download Content
-	contentURL := fmt.Sprintf("%s/videos/%s/content", baseURL, jobResp.ID)
+	// Download Content
+	videoData, err := withRetry(ctx, c.retry, func(ctx context.Context) ([]byte, error) {
+		return c.downloadVideo(ctx, baseURL, jobID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &VideoGenerateResponse{
+		Videos: []GeneratedVideo{{Data: videoData}},
+		Model:  req.Model,
+	}, nil
+}
+
+type openAIVideoStatus struct {
+	Status   string `json:"status"`
+	Progress *int   `json:"progress"`
+	Error    *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (c *OpenAIClient) getVideoStatus(ctx context.Context, baseURL, jobID string) (*openAIVideoStatus, error) {
+	statusURL := fmt.Sprintf("%s/videos/%s", baseURL, jobID)
+	statusReq, err := http.NewRequestWithContext(ctx, "GET", statusURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create status request: %w", err)
+	}
+	statusReq.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+
+	statusResp, err := c.httpClient.Do(statusReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check video status: %w", err)
+	}
+	defer statusResp.Body.Close()
+
+	body, _ := io.ReadAll(statusResp.Body)
+	if statusResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status check failed: %w", &HTTPStatusError{StatusCode: statusResp.StatusCode, Body: string(body)})
+	}
+
+	var statusObj openAIVideoStatus
+	if err := json.Unmarshal(body, &statusObj); err != nil {
+		return nil, fmt.Errorf("failed to decode status response: %w", err)
+	}
+	return &statusObj, nil
+}
+
+func (c *OpenAIClient) downloadVideo(ctx context.Context, baseURL, jobID string) ([]byte, error) {
+	contentURL := fmt.Sprintf("%s/videos/%s/content", baseURL, jobID)
 	contentReq, err := http.NewRequestWithContext(ctx, "GET", contentURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create download request: %w", err)
 	}
 	contentReq.Header.Set("Authorization", "Bearer "+c.config.APIKey)
 
-	contentResp, err := client.Do(contentReq)
+	contentResp, err := c.httpClient.Do(contentReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download video content: %w", err)
 	}
 	defer contentResp.Body.Close()
 
 	if contentResp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("download failed with status %d", contentResp.StatusCode)
+		body, _ := io.ReadAll(contentResp.Body)
+		return nil, fmt.Errorf("download failed: %w", &HTTPStatusError{StatusCode: contentResp.StatusCode, Body: string(body)})
 	}
 
 	videoData, err := io.ReadAll(contentResp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read video data: %w", err)
 	}
-
-	return &VideoGenerateResponse{
-		Data:  videoData,
-		Model: req.Model,
-	}, nil
+	return videoData, nil
 }
 
 // ListModels lists available models from OpenAI
 func (c *OpenAIClient) ListModels(ctx context.Context) ([]Model, error) {
-	list, err := c.client.ListModels(ctx)
+	list, err := withRetry(ctx, c.retry, func(ctx context.Context) (openai.ModelsList, error) {
+		return c.client.ListModels(ctx)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list OpenAI models: %w", err)
 	}
@@ -405,5 +1013,5 @@ func (c *OpenAIClient) ListModels(ctx context.Context) ([]Model, error) {
 		})
 	}
 
-	return models, nil
+	return annotateModels(c.config, models), nil
 }
@@ -0,0 +1,324 @@
+package ai
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"visionflow/database"
+)
+
+// ParameterType is the value type of a model parameter
+type ParameterType string
+
+const (
+	ParameterString  ParameterType = "string"
+	ParameterInteger ParameterType = "integer"
+	ParameterNumber  ParameterType = "number"
+	ParameterBoolean ParameterType = "boolean"
+)
+
+// Parameter describes a model specific setting passed in a request's Options under Name
+type Parameter struct {
+	Name        string        `json:"name"`
+	Label       string        `json:"label"`
+	Description string        `json:"description,omitempty"`
+	Type        ParameterType `json:"type"`
+	Min         *float64      `json:"min,omitempty"`
+	Max         *float64      `json:"max,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`    // Allowed values, of the parameter's type
+	Default     interface{}   `json:"default,omitempty"` // What the provider uses when the option is not set
+}
+
+// parameterFamily holds the parameters of a group of models sharing an ID prefix.
+// A family without prefixes applies to the provider's models no other family matched.
+type parameterFamily struct {
+	provider   database.AIProvider
+	prefixes   []string
+	parameters []Parameter
+}
+
+func bound(value float64) *float64 {
+	return &value
+}
+
+var (
+	topPParameter = Parameter{
+		Name: "top_p", Label: "Top P", Type: ParameterNumber, Min: bound(0), Max: bound(1), Default: 1.0,
+		Description: "Nucleus sampling, only the most likely tokens adding up to this probability are considered",
+	}
+	topKParameter = Parameter{
+		Name: "top_k", Label: "Top K", Type: ParameterInteger, Min: bound(1), Max: bound(500),
+		Description: "Only the K most likely tokens are considered",
+	}
+	seedParameter = Parameter{
+		Name: "seed", Label: "Seed", Type: ParameterInteger, Min: bound(0), Max: bound(math.MaxInt32),
+		Description: "Makes sampling repeatable on a best effort basis",
+	}
+)
+
+// parameterFamilies lists the known parameters per provider, more specific prefixes first
+var parameterFamilies = []parameterFamily{
+	{
+		provider: database.ProviderOpenAI,
+		prefixes: []string{"gpt-image"},
+		parameters: []Parameter{
+			{Name: "quality", Label: "Quality", Type: ParameterString, Enum: []interface{}{"low", "medium", "high", "auto"}, Default: "auto"},
+			{Name: "background", Label: "Background", Type: ParameterString, Enum: []interface{}{"transparent", "opaque", "auto"}, Default: "auto"},
+			{Name: "output_format", Label: "Output Format", Type: ParameterString, Enum: []interface{}{"png", "jpeg", "webp"}, Default: "png"},
+			{Name: "moderation", Label: "Moderation", Type: ParameterString, Enum: []interface{}{"low", "auto"}, Default: "auto"},
+		},
+	},
+	{
+		provider: database.ProviderOpenAI,
+		prefixes: []string{"dall-e-3"},
+		parameters: []Parameter{
+			{Name: "quality", Label: "Quality", Type: ParameterString, Enum: []interface{}{"standard", "hd"}, Default: "standard"},
+			{Name: "style", Label: "Style", Type: ParameterString, Enum: []interface{}{"vivid", "natural"}, Default: "vivid"},
+		},
+	},
+	{
+		provider: database.ProviderOpenAI,
+		prefixes: []string{"sora"},
+		parameters: []Parameter{
+			{Name: "seconds", Label: "Seconds", Type: ParameterInteger, Enum: []interface{}{4, 8, 12}, Default: 4},
+			{Name: "size", Label: "Size", Type: ParameterString, Enum: []interface{}{"720x1280", "1280x720", "1024x1792", "1792x1024"}, Default: "720x1280"},
+		},
+	},
+	{
+		provider: database.ProviderOpenAI,
+		prefixes: []string{"gpt-4o-mini-tts"},
+		parameters: []Parameter{
+			{Name: "instructions", Label: "Instructions", Type: ParameterString, Description: "How the voice should sound, e.g. calm and slow"},
+		},
+	},
+	{
+		provider: database.ProviderOpenAI,
+		prefixes: []string{"o1", "o3", "o4", "gpt-5"},
+		parameters: []Parameter{
+			{Name: "reasoning_effort", Label: "Reasoning Effort", Type: ParameterString, Enum: []interface{}{"minimal", "low", "medium", "high"}, Default: "medium"},
+			seedParameter,
+		},
+	},
+	{
+		// Models whose endpoints take no extra settings
+		provider: database.ProviderOpenAI,
+		prefixes: []string{"dall-e-2", "tts-", "whisper-", "gpt-4o-transcribe", "gpt-4o-mini-transcribe", "text-embedding-"},
+	},
+	{
+		provider: database.ProviderOpenAI,
+		parameters: []Parameter{
+			topPParameter,
+			{Name: "frequency_penalty", Label: "Frequency Penalty", Type: ParameterNumber, Min: bound(-2), Max: bound(2), Default: 0.0},
+			{Name: "presence_penalty", Label: "Presence Penalty", Type: ParameterNumber, Min: bound(-2), Max: bound(2), Default: 0.0},
+			seedParameter,
+		},
+	},
+	{
+		provider: database.ProviderGemini,
+		prefixes: []string{"imagen-4"},
+		parameters: []Parameter{
+			{Name: "image_size", Label: "Image Size", Type: ParameterString, Enum: []interface{}{"1K", "2K"}, Default: "1K"},
+		},
+	},
+	{
+		provider: database.ProviderGemini,
+		prefixes: []string{"imagen-", "veo-", "gemini-embedding", "text-embedding", "embedding-", "gemini-2.5-flash-image", "gemini-3-pro-image"},
+	},
+	{
+		provider: database.ProviderGemini,
+		prefixes: []string{"gemini-2.5", "gemini-3"},
+		parameters: []Parameter{
+			{
+				Name: "thinking_budget", Label: "Thinking Budget", Type: ParameterInteger, Min: bound(-1), Max: bound(32768), Default: -1,
+				Description: "Tokens the model may spend thinking, -1 lets it decide and 0 turns thinking off where allowed",
+			},
+			topPParameter,
+			topKParameter,
+		},
+	},
+	{
+		provider:   database.ProviderGemini,
+		parameters: []Parameter{topPParameter, topKParameter},
+	},
+	{
+		provider: database.ProviderClaude,
+		prefixes: []string{"claude-3-7", "claude-sonnet-4", "claude-opus-4", "claude-haiku-4"},
+		parameters: []Parameter{
+			{
+				Name: "thinking_budget", Label: "Thinking Budget", Type: ParameterInteger, Min: bound(0), Max: bound(64000), Default: 0,
+				Description: "Tokens for extended thinking, 0 turns it off and otherwise at least 1024",
+			},
+			topPParameter,
+			topKParameter,
+		},
+	},
+	{
+		provider:   database.ProviderClaude,
+		parameters: []Parameter{topPParameter, topKParameter},
+	},
+	{
+		provider: database.ProviderOllama,
+		parameters: []Parameter{
+			{Name: "num_ctx", Label: "Context Length", Type: ParameterInteger, Min: bound(256), Max: bound(1 << 20), Default: 4096},
+			topPParameter,
+			topKParameter,
+			{Name: "repeat_penalty", Label: "Repeat Penalty", Type: ParameterNumber, Min: bound(0), Max: bound(2), Default: 1.1},
+			seedParameter,
+		},
+	},
+}
+
+// ModelParameters returns the parameters a provider's model accepts in Options
+func ModelParameters(provider database.AIProvider, model string) []Parameter {
+	model = strings.ToLower(strings.TrimPrefix(model, "models/"))
+
+	var fallback []Parameter
+	for _, family := range parameterFamilies {
+		if family.provider != provider {
+			continue
+		}
+		if len(family.prefixes) == 0 {
+			if fallback == nil {
+				fallback = family.parameters
+			}
+			continue
+		}
+		for _, prefix := range family.prefixes {
+			if strings.HasPrefix(model, prefix) {
+				return slices.Clone(family.parameters)
+			}
+		}
+	}
+	return slices.Clone(fallback)
+}
+
+// modelOptions are request options checked against the model's parameters.
+// Integers are stored as int, numbers as float64.
+type modelOptions map[string]interface{}
+
+// resolveOptions validates options against the parameters of a provider's model
+func resolveOptions(provider database.AIProvider, model string, options map[string]interface{}) (modelOptions, error) {
+	if len(options) == 0 {
+		return modelOptions{}, nil
+	}
+
+	parameters := ModelParameters(provider, model)
+	resolved := modelOptions{}
+	for name, value := range options {
+		index := slices.IndexFunc(parameters, func(p Parameter) bool { return p.Name == name })
+		if index < 0 {
+			known := make([]string, 0, len(parameters))
+			for _, p := range parameters {
+				known = append(known, p.Name)
+			}
+			sort.Strings(known)
+			if len(known) == 0 {
+				return nil, fmt.Errorf("model %s takes no options, got %q", model, name)
+			}
+			return nil, fmt.Errorf("unknown option %q for model %s, use one of %s", name, model, strings.Join(known, ", "))
+		}
+
+		converted, err := parameters[index].convert(value)
+		if err != nil {
+			return nil, fmt.Errorf("option %q: %w", name, err)
+		}
+		resolved[name] = converted
+	}
+	return resolved, nil
+}
+
+// convert checks a JSON decoded value against the parameter and converts it to its Go type
+func (p Parameter) convert(value interface{}) (interface{}, error) {
+	var converted interface{}
+	switch p.Type {
+	case ParameterString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %T", value)
+		}
+		converted = s
+	case ParameterBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected a boolean, got %T", value)
+		}
+		converted = b
+	case ParameterInteger, ParameterNumber:
+		number, ok := toFloat(value)
+		if !ok {
+			return nil, fmt.Errorf("expected a number, got %T", value)
+		}
+		if p.Min != nil && number < *p.Min {
+			return nil, fmt.Errorf("%v is below the minimum %v", number, *p.Min)
+		}
+		if p.Max != nil && number > *p.Max {
+			return nil, fmt.Errorf("%v is above the maximum %v", number, *p.Max)
+		}
+		if p.Type == ParameterNumber {
+			converted = number
+			break
+		}
+		if number != math.Trunc(number) {
+			return nil, fmt.Errorf("expected an integer, got %v", number)
+		}
+		converted = int(number)
+	default:
+		return nil, fmt.Errorf("unsupported parameter type %q", p.Type)
+	}
+
+	if len(p.Enum) > 0 && !slices.ContainsFunc(p.Enum, func(allowed interface{}) bool {
+		if number, ok := toFloat(allowed); ok {
+			other, _ := toFloat(converted)
+			return number == other
+		}
+		return allowed == converted
+	}) {
+		return nil, fmt.Errorf("%v is not one of %v", value, p.Enum)
+	}
+	return converted, nil
+}
+
+// toFloat converts the numeric types found in JSON decoded and Go literal options
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func (o modelOptions) string(name string) (string, bool) {
+	value, ok := o[name].(string)
+	return value, ok
+}
+
+func (o modelOptions) int(name string) (int, bool) {
+	value, ok := o[name].(int)
+	return value, ok
+}
+
+func (o modelOptions) float(name string) (float64, bool) {
+	value, ok := o[name].(float64)
+	return value, ok
+}
+
+// applyImageOptions copies options that have a typed request field, fields set by the caller win
+func (req *ImageGenerateRequest) applyImageOptions(options modelOptions) {
+	if quality, ok := options.string("quality"); ok && req.Quality == "" {
+		req.Quality = quality
+	}
+	if style, ok := options.string("style"); ok && req.Style == "" {
+		req.Style = style
+	}
+}
@@ -0,0 +1,151 @@
+package ai
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"visionflow/database"
+)
+
+func TestModelParameters(t *testing.T) {
+	tests := []struct {
+		provider database.AIProvider
+		model    string
+		want     []string
+	}{
+		{database.ProviderOpenAI, "gpt-image-1", []string{"quality", "background", "output_format", "moderation"}},
+		{database.ProviderOpenAI, "GPT-5-mini", []string{"reasoning_effort", "seed"}},
+		{database.ProviderOpenAI, "whisper-1", nil},
+		{database.ProviderOpenAI, "gpt-4o", []string{"top_p", "frequency_penalty", "presence_penalty", "seed"}},
+		{database.ProviderGemini, "models/imagen-4.0-generate-001", []string{"image_size"}},
+		{database.ProviderGemini, "veo-3.0-generate-001", nil},
+		{database.ProviderGemini, "gemini-1.5-pro", []string{"top_p", "top_k"}},
+		{database.ProviderMock, "anything", nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, p := range ModelParameters(tt.provider, tt.model) {
+			got = append(got, p.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ModelParameters(%s, %s) = %v, want %v", tt.provider, tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestResolveOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider database.AIProvider
+		model    string
+		options  map[string]interface{}
+		want     modelOptions
+		wantErr  string
+	}{
+		{
+			name:     "no options",
+			provider: database.ProviderOpenAI,
+			model:    "gpt-4o",
+			want:     modelOptions{},
+		},
+		{
+			name:     "JSON numbers become ints and floats",
+			provider: database.ProviderOllama,
+			model:    "llama3.2",
+			options:  map[string]interface{}{"num_ctx": 8192.0, "top_p": 0.9, "seed": 7},
+			want:     modelOptions{"num_ctx": 8192, "top_p": 0.9, "seed": 7},
+		},
+		{
+			name:     "integer enum accepts a JSON number",
+			provider: database.ProviderOpenAI,
+			model:    "sora-2",
+			options:  map[string]interface{}{"seconds": 8.0},
+			want:     modelOptions{"seconds": 8},
+		},
+		{
+			name:     "unknown option lists the known ones",
+			provider: database.ProviderClaude,
+			model:    "claude-3-5-haiku",
+			options:  map[string]interface{}{"seed": 1},
+			wantErr:  `unknown option "seed" for model claude-3-5-haiku, use one of top_k, top_p`,
+		},
+		{
+			name:     "model without parameters",
+			provider: database.ProviderOpenAI,
+			model:    "dall-e-2",
+			options:  map[string]interface{}{"quality": "hd"},
+			wantErr:  `model dall-e-2 takes no options, got "quality"`,
+		},
+		{
+			name:     "value outside the enum",
+			provider: database.ProviderOpenAI,
+			model:    "sora-2",
+			options:  map[string]interface{}{"seconds": 5},
+			wantErr:  `option "seconds": 5 is not one of`,
+		},
+		{
+			name:     "value below the minimum",
+			provider: database.ProviderGemini,
+			model:    "gemini-2.5-flash",
+			options:  map[string]interface{}{"thinking_budget": -2},
+			wantErr:  "-2 is below the minimum -1",
+		},
+		{
+			name:     "fraction for an integer",
+			provider: database.ProviderGemini,
+			model:    "gemini-2.5-flash",
+			options:  map[string]interface{}{"top_k": 2.5},
+			wantErr:  "expected an integer, got 2.5",
+		},
+		{
+			name:     "wrong type",
+			provider: database.ProviderOpenAI,
+			model:    "dall-e-3",
+			options:  map[string]interface{}{"style": true},
+			wantErr:  "expected a string, got bool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveOptions(tt.provider, tt.model, tt.options)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParameterConvert(t *testing.T) {
+	boolean := Parameter{Name: "flag", Type: ParameterBoolean}
+	if got, err := boolean.convert(true); err != nil || got != true {
+		t.Errorf("boolean convert = %v, %v", got, err)
+	}
+	if _, err := boolean.convert("true"); err == nil {
+		t.Error("boolean convert accepted a string")
+	}
+
+	number := Parameter{Name: "penalty", Type: ParameterNumber, Max: bound(2)}
+	if got, err := number.convert(1); err != nil || got != 1.0 {
+		t.Errorf("number convert = %#v, %v, want float64 1", got, err)
+	}
+	if _, err := number.convert(2.5); err == nil {
+		t.Error("number convert accepted a value above the maximum")
+	}
+
+	unknown := Parameter{Name: "x", Type: "date"}
+	if _, err := unknown.convert("2026-01-01"); err == nil {
+		t.Error("convert accepted an unsupported parameter type")
+	}
+}
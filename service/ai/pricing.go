@@ -0,0 +1,103 @@
+package ai
+
+import (
+	"strconv"
+	"strings"
+)
+
+// UsageAmount describes what a single generation call consumed
+type UsageAmount struct {
+	PromptTokens int
+	OutputTokens int
+	Images       int
+	VideoSeconds float64
+	Characters   int     // Input characters of a text-to-speech call
+	AudioSeconds float64 // Length of the media of a transcription call
+}
+
+// mediaPrice is a per unit price in USD for models that models.dev does not price by token
+type mediaPrice struct {
+	PerImage             float64
+	PerVideoSecond       float64
+	PerMillionCharacters float64
+	PerAudioSecond       float64
+	DefaultVideoSeconds  float64 // Clip length the provider renders when no duration is requested
+}
+
+// mediaPrices holds list prices of image, video and speech models, matched by model ID prefix.
+// The longest matching prefix wins, so specific variants must be listed next to their family.
+var mediaPrices = map[string]mediaPrice{
+	"dall-e-2":               {PerImage: 0.02},
+	"dall-e-3":               {PerImage: 0.04},
+	"gpt-image-1-mini":       {PerImage: 0.011},
+	"gpt-image-1":            {PerImage: 0.042},
+	"imagen-3":               {PerImage: 0.03},
+	"imagen-4":               {PerImage: 0.04},
+	"imagen-4.0-fast":        {PerImage: 0.02},
+	"imagen-4.0-ultra":       {PerImage: 0.06},
+	"gemini-2.5-flash-image": {PerImage: 0.039},
+	"sora-2":                 {PerVideoSecond: 0.10, DefaultVideoSeconds: 4},
+	"sora-2-pro":             {PerVideoSecond: 0.30, DefaultVideoSeconds: 4},
+	"veo-2":                  {PerVideoSecond: 0.35, DefaultVideoSeconds: 8},
+	"veo-3":                  {PerVideoSecond: 0.40, DefaultVideoSeconds: 8},
+	"veo-3.0-fast":           {PerVideoSecond: 0.15, DefaultVideoSeconds: 8},
+	"veo-3.1-fast":           {PerVideoSecond: 0.15, DefaultVideoSeconds: 8},
+	"tts-1":                  {PerMillionCharacters: 15},
+	"tts-1-hd":               {PerMillionCharacters: 30},
+	"whisper-1":              {PerAudioSecond: 0.0001},
+	"gpt-4o-transcribe":      {PerAudioSecond: 0.0001},
+	"gpt-4o-mini-transcribe": {PerAudioSecond: 0.00005},
+}
+
+func lookupMediaPrice(modelID string) (mediaPrice, bool) {
+	modelID = strings.ToLower(strings.TrimPrefix(modelID, "models/"))
+	var best string
+	for prefix := range mediaPrices {
+		if strings.HasPrefix(modelID, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return mediaPrice{}, false
+	}
+	return mediaPrices[best], true
+}
+
+// EstimateVideoSeconds returns the clip length a video request will be billed for.
+// duration is the user supplied value such as "8" or "8s"; empty falls back to the model default.
+func EstimateVideoSeconds(modelID, duration string) float64 {
+	duration = strings.TrimSuffix(strings.TrimSpace(duration), "s")
+	if seconds, err := strconv.ParseFloat(duration, 64); err == nil && seconds > 0 {
+		return seconds
+	}
+	if price, ok := lookupMediaPrice(modelID); ok && price.DefaultVideoSeconds > 0 {
+		return price.DefaultVideoSeconds
+	}
+	return 8
+}
+
+// EstimateCost prices a call in USD. Tokens use the models.dev data, media falls back to mediaPrices.
+// The second result is false when nothing the call consumed could be priced.
+func EstimateCost(modelID string, amount UsageAmount) (float64, bool) {
+	var cost float64
+	priced := false
+
+	if amount.PromptTokens > 0 || amount.OutputTokens > 0 {
+		if tokenCost := GetModelCost(modelID); tokenCost != nil {
+			cost += float64(amount.PromptTokens)*tokenCost.Input/1e6 + float64(amount.OutputTokens)*tokenCost.Output/1e6
+			priced = true
+		}
+	}
+
+	if amount.Images > 0 || amount.VideoSeconds > 0 || amount.Characters > 0 || amount.AudioSeconds > 0 {
+		if price, ok := lookupMediaPrice(modelID); ok {
+			cost += float64(amount.Images)*price.PerImage +
+				amount.VideoSeconds*price.PerVideoSecond +
+				float64(amount.Characters)*price.PerMillionCharacters/1e6 +
+				amount.AudioSeconds*price.PerAudioSecond
+			priced = true
+		}
+	}
+
+	return cost, priced
+}
@@ -0,0 +1,81 @@
+package ai
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLookupMediaPrice(t *testing.T) {
+	tests := []struct {
+		model string
+		want  mediaPrice
+		found bool
+	}{
+		{"dall-e-3", mediaPrices["dall-e-3"], true},
+		{"imagen-4.0-generate-001", mediaPrices["imagen-4"], true},
+		{"imagen-4.0-fast-generate-001", mediaPrices["imagen-4.0-fast"], true},
+		{"models/veo-3.0-fast-generate-001", mediaPrices["veo-3.0-fast"], true},
+		{"VEO-3.0-generate-001", mediaPrices["veo-3"], true},
+		{"sora-2-pro", mediaPrices["sora-2-pro"], true},
+		{"gpt-4o", mediaPrice{}, false},
+	}
+
+	for _, tt := range tests {
+		got, found := lookupMediaPrice(tt.model)
+		if got != tt.want || found != tt.found {
+			t.Errorf("lookupMediaPrice(%q) = %+v, %v, want %+v, %v", tt.model, got, found, tt.want, tt.found)
+		}
+	}
+}
+
+func TestEstimateVideoSeconds(t *testing.T) {
+	tests := []struct {
+		model, duration string
+		want            float64
+	}{
+		{"sora-2", "8", 8},
+		{"sora-2", " 12s ", 12},
+		{"sora-2", "", 4},
+		{"veo-3.0-generate-001", "", 8},
+		{"veo-3.0-generate-001", "-1", 8},
+		{"unknown-video-model", "", 8},
+	}
+
+	for _, tt := range tests {
+		if got := EstimateVideoSeconds(tt.model, tt.duration); got != tt.want {
+			t.Errorf("EstimateVideoSeconds(%q, %q) = %v, want %v", tt.model, tt.duration, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	tokenCost := GetModelCost("gpt-4o")
+	if tokenCost == nil {
+		t.Fatal("embedded model data has no price for gpt-4o")
+	}
+
+	tests := []struct {
+		name   string
+		model  string
+		amount UsageAmount
+		want   float64
+		priced bool
+	}{
+		{"tokens", "gpt-4o", UsageAmount{PromptTokens: 1000, OutputTokens: 500}, 1000*tokenCost.Input/1e6 + 500*tokenCost.Output/1e6, true},
+		{"images", "gpt-image-1", UsageAmount{Images: 3}, 3 * 0.042, true},
+		{"video seconds", "veo-3.0-fast-generate-001", UsageAmount{VideoSeconds: 8}, 8 * 0.15, true},
+		{"speech characters", "tts-1-hd", UsageAmount{Characters: 2000}, 2000 * 30 / 1e6, true},
+		{"transcribed seconds", "whisper-1", UsageAmount{AudioSeconds: 60}, 60 * 0.0001, true},
+		{"unknown model", "my-local-model", UsageAmount{PromptTokens: 1000, Images: 1}, 0, false},
+		{"nothing consumed", "gpt-4o", UsageAmount{}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, priced := EstimateCost(tt.model, tt.amount)
+			if priced != tt.priced || math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("EstimateCost = %v, %v, want %v, %v", got, priced, tt.want, tt.priced)
+			}
+		})
+	}
+}
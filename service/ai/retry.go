@@ -0,0 +1,311 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"visionflow/database"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/sashabaranov/go-openai"
+	"google.golang.org/genai"
+)
+
+// RetryPolicy controls how transient provider failures (429, 5xx, overloaded) are retried
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first one
+	BaseDelay   time.Duration // First backoff step, doubled on every retry
+	MaxDelay    time.Duration // Upper bound for a single backoff step
+	Budget      time.Duration // Upper bound for the total time spent waiting
+}
+
+var defaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   time.Second,
+	MaxDelay:    30 * time.Second,
+	Budget:      2 * time.Minute,
+}
+
+// defaultRetryPolicies holds the per provider defaults, local servers fail fast
+var defaultRetryPolicies = map[database.AIProvider]RetryPolicy{
+	database.ProviderOllama: {
+		MaxAttempts: 2,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Budget:      10 * time.Second,
+	},
+	database.ProviderMock: {
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    time.Second,
+		Budget:      5 * time.Second,
+	},
+}
+
+// retryPolicyFor returns the provider default overridden by the provider configuration
+func retryPolicyFor(config database.ModelProvider) RetryPolicy {
+	policy, ok := defaultRetryPolicies[config.Type]
+	if !ok {
+		policy = defaultRetryPolicy
+	}
+	if config.RetryMaxAttempts > 0 {
+		policy.MaxAttempts = config.RetryMaxAttempts
+	}
+	if config.RetryBudgetSeconds > 0 {
+		policy.Budget = time.Duration(config.RetryBudgetSeconds) * time.Second
+	}
+	return policy
+}
+
+// HTTPStatusError is returned by the clients that talk to a provider over plain HTTP
+type HTTPStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.StatusCode, e.Body)
+}
+
+// permanentError marks an error that must not be retried, e.g. a stream that already emitted output
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// retryHint carries the server requested delay from the transport back to withRetry
+type retryHint struct {
+	mu    sync.Mutex
+	after time.Duration
+}
+
+type retryHintKey struct{}
+
+// retryTransport records Retry-After and rate-limit reset headers of failed responses.
+// The SDK errors do not all expose headers, so the hint travels through the request context.
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || !isRetryableStatus(resp.StatusCode) {
+		return resp, err
+	}
+	if hint, ok := req.Context().Value(retryHintKey{}).(*retryHint); ok {
+		if after := retryAfterFromHeader(resp.Header); after > 0 {
+			hint.mu.Lock()
+			hint.after = after
+			hint.mu.Unlock()
+		}
+	}
+	return resp, err
+}
+
+// newHTTPClient returns an HTTP client whose failed responses feed the retry hints
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Transport: &retryTransport{base: http.DefaultTransport},
+	}
+}
+
+// retryAfterFromHeader reads the delay requested by the server, if any
+func retryAfterFromHeader(header http.Header) time.Duration {
+	if ms := header.Get("Retry-After-Ms"); ms != "" {
+		if value, err := strconv.ParseFloat(ms, 64); err == nil && value > 0 {
+			return time.Duration(value * float64(time.Millisecond))
+		}
+	}
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+			return time.Duration(seconds * float64(time.Second))
+		}
+		if at, err := http.ParseTime(value); err == nil {
+			return time.Until(at)
+		}
+	}
+
+	// OpenAI style durations, e.g. "6m0s" or "20ms"
+	var longest time.Duration
+	for _, key := range []string{"X-Ratelimit-Reset-Requests", "X-Ratelimit-Reset-Tokens"} {
+		if d, err := time.ParseDuration(header.Get(key)); err == nil && d > longest {
+			longest = d
+		}
+	}
+	// Anthropic style RFC 3339 timestamps
+	for _, key := range []string{"Anthropic-Ratelimit-Requests-Reset", "Anthropic-Ratelimit-Tokens-Reset"} {
+		if at, err := time.Parse(time.RFC3339, header.Get(key)); err == nil {
+			if d := time.Until(at); d > longest {
+				longest = d
+			}
+		}
+	}
+	return longest
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout,
+		529: // Anthropic overloaded
+		return true
+	}
+	return false
+}
+
+// isRetryable reports whether err is a transient provider failure worth another attempt
+func isRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+
+	var openaiAPIErr *openai.APIError
+	if errors.As(err, &openaiAPIErr) {
+		return isRetryableStatus(openaiAPIErr.HTTPStatusCode)
+	}
+	var openaiReqErr *openai.RequestError
+	if errors.As(err, &openaiReqErr) {
+		return isRetryableStatus(openaiReqErr.HTTPStatusCode)
+	}
+	var anthropicErr *anthropic.Error
+	if errors.As(err, &anthropicErr) {
+		return isRetryableStatus(anthropicErr.StatusCode) || strings.Contains(anthropicErr.Error(), "overloaded_error")
+	}
+	var genaiErr genai.APIError
+	if errors.As(err, &genaiErr) {
+		return isRetryableStatus(genaiErr.Code) || genaiErr.Status == "UNAVAILABLE" || genaiErr.Status == "RESOURCE_EXHAUSTED"
+	}
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return isRetryableStatus(statusErr.StatusCode)
+	}
+
+	// Dropped connections and timeouts on the way to the provider
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// Overload reported inside an already open event stream has no status code
+	return strings.Contains(err.Error(), "overloaded_error")
+}
+
+// errorStatus returns the HTTP status carried by a provider error, or 0 when there is none
+func errorStatus(err error) int {
+	var openaiAPIErr *openai.APIError
+	if errors.As(err, &openaiAPIErr) {
+		return openaiAPIErr.HTTPStatusCode
+	}
+	var openaiReqErr *openai.RequestError
+	if errors.As(err, &openaiReqErr) {
+		return openaiReqErr.HTTPStatusCode
+	}
+	var anthropicErr *anthropic.Error
+	if errors.As(err, &anthropicErr) {
+		return anthropicErr.StatusCode
+	}
+	var genaiErr genai.APIError
+	if errors.As(err, &genaiErr) {
+		return genaiErr.Code
+	}
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode
+	}
+	return 0
+}
+
+// isRetryableCreate reports whether a call that starts paid work certainly created nothing and may be sent again:
+// the server refused it and asked to come back later, or the connection was never established.
+// Timeouts and other 5xx may arrive after the provider accepted the request, retrying them could bill twice.
+func isRetryableCreate(err error, hinted bool) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+
+	switch errorStatus(err) {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return hinted
+	case 0:
+	default:
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// withRetry runs op until it succeeds, fails permanently, or the policy is exhausted.
+// Waits honour the server requested delay and otherwise use jittered exponential backoff.
+func withRetry[T any](ctx context.Context, policy RetryPolicy, op func(ctx context.Context) (T, error)) (T, error) {
+	return retry(ctx, policy, func(err error, hinted bool) bool { return isRetryable(err) }, op)
+}
+
+// withCreateRetry is withRetry for calls that start paid work at the provider, e.g. an image or a video job.
+// Only failures isRetryableCreate proves harmless are retried, polling and downloads use withRetry.
+func withCreateRetry[T any](ctx context.Context, policy RetryPolicy, op func(ctx context.Context) (T, error)) (T, error) {
+	return retry(ctx, policy, isRetryableCreate, op)
+}
+
+func retry[T any](ctx context.Context, policy RetryPolicy, retryable func(err error, hinted bool) bool, op func(ctx context.Context) (T, error)) (T, error) {
+	var waited time.Duration
+	for attempt := 1; ; attempt++ {
+		hint := &retryHint{}
+		result, err := op(context.WithValue(ctx, retryHintKey{}, hint))
+		if err == nil || attempt >= policy.MaxAttempts {
+			return result, err
+		}
+
+		hint.mu.Lock()
+		delay := hint.after
+		hint.mu.Unlock()
+		if !retryable(err, delay > 0) {
+			return result, err
+		}
+		if delay <= 0 {
+			backoff := policy.BaseDelay << (attempt - 1)
+			if backoff > policy.MaxDelay || backoff <= 0 {
+				backoff = policy.MaxDelay
+			}
+			// Jitter spreads out clients that failed at the same moment
+			delay = backoff/2 + rand.N(backoff/2+1)
+		}
+		// A server requested delay may exceed MaxDelay, only the total budget bounds it
+		if waited+delay > policy.Budget {
+			return result, err
+		}
+		waited += delay
+
+		fmt.Printf("retrying after %v (attempt %d/%d): %v\n", delay, attempt+1, policy.MaxAttempts, err)
+
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-time.After(delay):
+		}
+	}
+}
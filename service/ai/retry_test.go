@@ -0,0 +1,59 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestIsRetryableCreate(t *testing.T) {
+	dial := &url.Error{Op: "Post", URL: "https://api.example.com", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	read := &url.Error{Op: "Post", URL: "https://api.example.com", Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset")}}
+
+	tests := []struct {
+		name   string
+		err    error
+		hinted bool
+		want   bool
+	}{
+		{"rate limited with Retry-After", &HTTPStatusError{StatusCode: 429}, true, true},
+		{"unavailable with Retry-After", fmt.Errorf("wrapped: %w", &HTTPStatusError{StatusCode: 503}), true, true},
+		{"rate limited without Retry-After", &HTTPStatusError{StatusCode: 429}, false, false},
+		{"bad gateway", &HTTPStatusError{StatusCode: 502}, true, false},
+		{"gateway timeout", &HTTPStatusError{StatusCode: 504}, false, false},
+		{"connection never established", dial, false, true},
+		{"unresolvable host", &net.DNSError{Err: "no such host", Name: "api.example.com"}, false, true},
+		{"connection dropped after sending", read, false, false},
+		{"cancelled", context.Canceled, false, false},
+		{"permanent", &permanentError{dial}, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryableCreate(tt.err, tt.hinted); got != tt.want {
+			t.Errorf("%s: isRetryableCreate = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWithCreateRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond, Budget: time.Second}
+	failing := func(attempts *int) func(ctx context.Context) (string, error) {
+		return func(ctx context.Context) (string, error) {
+			*attempts++
+			return "", &HTTPStatusError{StatusCode: 502}
+		}
+	}
+
+	// Polling may be repeated freely, a creation call that may have reached the provider must not be
+	var polls, creates int
+	if _, err := withRetry(context.Background(), policy, failing(&polls)); err == nil || polls != 3 {
+		t.Errorf("withRetry made %d attempts (err %v), want 3", polls, err)
+	}
+	if _, err := withCreateRetry(context.Background(), policy, failing(&creates)); err == nil || creates != 1 {
+		t.Errorf("withCreateRetry made %d attempts (err %v), want 1", creates, err)
+	}
+}
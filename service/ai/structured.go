@@ -0,0 +1,108 @@
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	ResponseFormatText       = "text"        // Free-form text, the default
+	ResponseFormatJSONObject = "json_object" // Any JSON object
+	ResponseFormatJSONSchema = "json_schema" // JSON matching Schema
+)
+
+// ResponseFormat asks a text model for structured output instead of free-form text
+type ResponseFormat struct {
+	Type   string                 `json:"type"`
+	Name   string                 `json:"name,omitempty"`   // Schema name, some providers require one
+	Schema map[string]interface{} `json:"schema,omitempty"` // JSON Schema, required for json_schema
+	Strict bool                   `json:"strict,omitempty"` // OpenAI strict mode, the schema must then list every property as required
+}
+
+func (f *ResponseFormat) structured() bool {
+	return f != nil && f.Type != "" && f.Type != ResponseFormatText
+}
+
+func (f *ResponseFormat) schemaName() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	return "response"
+}
+
+// validate rejects formats that cannot be mapped onto any provider
+func (f *ResponseFormat) validate() error {
+	if f == nil {
+		return nil
+	}
+	switch f.Type {
+	case "", ResponseFormatText, ResponseFormatJSONObject:
+		return nil
+	case ResponseFormatJSONSchema:
+		if len(f.Schema) == 0 {
+			return errors.New("response format json_schema requires a schema")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown response format %q", f.Type)
+	}
+}
+
+// StructuredOutputError is returned when a model's answer does not satisfy the requested ResponseFormat.
+// Content holds the raw answer so callers can still show it.
+type StructuredOutputError struct {
+	Content string
+	Err     error
+}
+
+func (e *StructuredOutputError) Error() string {
+	return fmt.Sprintf("structured output is invalid: %v", e.Err)
+}
+
+func (e *StructuredOutputError) Unwrap() error { return e.Err }
+
+// checkStructuredOutput validates resp against the request's ResponseFormat and normalises
+// its content to the bare JSON document.
+func checkStructuredOutput(req TextGenerateRequest, resp *TextGenerateResponse) error {
+	format := req.ResponseFormat
+	// A turn that calls tools carries no answer yet
+	if !format.structured() || len(resp.ToolCalls) > 0 {
+		return nil
+	}
+
+	content := extractJSON(resp.Content)
+	var value interface{}
+	if err := json.Unmarshal([]byte(content), &value); err != nil {
+		return &StructuredOutputError{Content: resp.Content, Err: fmt.Errorf("not valid JSON: %w", err)}
+	}
+
+	if format.Type == ResponseFormatJSONObject {
+		if _, ok := value.(map[string]interface{}); !ok {
+			return &StructuredOutputError{Content: resp.Content, Err: errors.New("expected a JSON object")}
+		}
+	}
+	if len(format.Schema) > 0 {
+		if err := validateSchema(value, format.Schema); err != nil {
+			return &StructuredOutputError{Content: resp.Content, Err: err}
+		}
+	}
+
+	resp.Content = content
+	return nil
+}
+
+// extractJSON strips the markdown code fence some models wrap JSON in despite being asked not to
+func extractJSON(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "```") {
+		return content
+	}
+	content = strings.TrimPrefix(content, "```")
+	if newline := strings.Index(content, "\n"); newline >= 0 {
+		content = content[newline+1:] // Drop the language tag
+	}
+	content = strings.TrimSuffix(strings.TrimSpace(content), "```")
+	return strings.TrimSpace(content)
+}
@@ -0,0 +1,216 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	ToolChoiceAuto     = "auto"     // The model decides, the default
+	ToolChoiceNone     = "none"     // The model must answer with text
+	ToolChoiceRequired = "required" // The model must call at least one tool
+	// Any other ToolChoice value forces the tool with that name
+)
+
+// Tool describes a function the model may call
+type Tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"` // JSON Schema of the arguments object
+}
+
+// ToolCall is a model's request to run a tool
+type ToolCall struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"` // JSON object
+	// Signature is opaque provider data that must be sent back with the call, e.g. Gemini thought signatures
+	Signature string `json:"signature,omitempty"`
+}
+
+// ToolResult is the output of a tool call, sent back to the model in a tool message
+type ToolResult struct {
+	CallID  string `json:"callId"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	IsError bool   `json:"isError,omitempty"`
+}
+
+// toolParameters returns the tool's argument schema, models require an object schema even without arguments
+func (t Tool) toolParameters() map[string]interface{} {
+	if len(t.Parameters) > 0 {
+		return t.Parameters
+	}
+	return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+}
+
+// validateTools rejects tool settings that cannot be mapped onto any provider
+func (req TextGenerateRequest) validateTools() error {
+	names := map[string]bool{}
+	for _, tool := range req.Tools {
+		if tool.Name == "" {
+			return errors.New("tool name is required")
+		}
+		if names[tool.Name] {
+			return fmt.Errorf("duplicate tool %q", tool.Name)
+		}
+		names[tool.Name] = true
+	}
+
+	switch req.ToolChoice {
+	case "", ToolChoiceAuto, ToolChoiceNone, ToolChoiceRequired:
+	default:
+		if !names[req.ToolChoice] {
+			return fmt.Errorf("tool choice %q is not one of the request's tools", req.ToolChoice)
+		}
+	}
+	return nil
+}
+
+// ToolHandler runs a tool with its JSON arguments and returns the result sent back to the model
+type ToolHandler func(ctx context.Context, arguments json.RawMessage) (string, error)
+
+type registeredTool struct {
+	tool    Tool
+	handler ToolHandler
+}
+
+// ToolRegistry holds the Go functions a model may call through RunWithTools
+type ToolRegistry struct {
+	mu    sync.RWMutex
+	tools map[string]registeredTool
+	order []string
+}
+
+// NewToolRegistry creates an empty tool registry
+func NewToolRegistry() *ToolRegistry {
+	return &ToolRegistry{tools: map[string]registeredTool{}}
+}
+
+// Register adds a tool, names must be unique
+func (r *ToolRegistry) Register(tool Tool, handler ToolHandler) error {
+	if tool.Name == "" {
+		return errors.New("tool name is required")
+	}
+	if handler == nil {
+		return fmt.Errorf("tool %q has no handler", tool.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tools[tool.Name]; exists {
+		return fmt.Errorf("tool %q is already registered", tool.Name)
+	}
+	r.tools[tool.Name] = registeredTool{tool: tool, handler: handler}
+	r.order = append(r.order, tool.Name)
+	return nil
+}
+
+// Tools lists the registered tools in registration order
+func (r *ToolRegistry) Tools() []Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tools := make([]Tool, 0, len(r.order))
+	for _, name := range r.order {
+		tools = append(tools, r.tools[name].tool)
+	}
+	return tools
+}
+
+// Call runs a tool call. Unknown tools, invalid arguments and handler errors are reported
+// back to the model as error results so it can correct itself.
+func (r *ToolRegistry) Call(ctx context.Context, call ToolCall) ToolResult {
+	result := ToolResult{CallID: call.ID, Name: call.Name}
+
+	r.mu.RLock()
+	registered, ok := r.tools[call.Name]
+	r.mu.RUnlock()
+	if !ok {
+		result.Content = fmt.Sprintf("unknown tool %q", call.Name)
+		result.IsError = true
+		return result
+	}
+
+	arguments := json.RawMessage(call.Arguments)
+	if len(arguments) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(arguments, &value); err != nil {
+		result.Content = fmt.Sprintf("arguments are not valid JSON: %v", err)
+		result.IsError = true
+		return result
+	}
+	if err := validateSchema(value, registered.tool.toolParameters()); err != nil {
+		result.Content = fmt.Sprintf("invalid arguments: %v", err)
+		result.IsError = true
+		return result
+	}
+
+	content, err := registered.handler(ctx, arguments)
+	if err != nil {
+		result.Content = err.Error()
+		result.IsError = true
+		return result
+	}
+	result.Content = content
+	return result
+}
+
+const defaultMaxToolSteps = 8
+
+// RunWithTools sends req with the registry's tools, runs every tool the model calls and feeds the
+// results back until the model answers without calling a tool or maxSteps requests were made.
+// It returns the final response, with usage summed over all steps, and the full conversation.
+func RunWithTools(ctx context.Context, client AIClient, req TextGenerateRequest, registry *ToolRegistry, maxSteps int) (*TextGenerateResponse, []Message, error) {
+	if maxSteps <= 0 {
+		maxSteps = defaultMaxToolSteps
+	}
+	req.Tools = append(append([]Tool{}, req.Tools...), registry.Tools()...)
+
+	// The prompt becomes the first turn of the history so later steps only append to it
+	messages, err := req.conversation()
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Prompt, req.Images, req.Videos, req.Audios, req.Documents = "", nil, nil, nil, nil
+
+	total := &TextGenerateResponse{}
+	for step := 0; step < maxSteps; step++ {
+		req.Messages = messages
+		resp, err := client.GenerateText(ctx, req)
+		if err != nil {
+			return nil, messages, err
+		}
+
+		total.PromptTokens += resp.PromptTokens
+		total.OutputTokens += resp.OutputTokens
+		total.TotalTokens += resp.TotalTokens
+		total.Model = resp.Model
+		total.Content = resp.Content
+
+		messages = append(messages, Message{Role: RoleAssistant, Content: resp.Content, ToolCalls: resp.ToolCalls})
+		if len(resp.ToolCalls) == 0 {
+			return total, messages, nil
+		}
+
+		results := make([]ToolResult, 0, len(resp.ToolCalls))
+		for _, call := range resp.ToolCalls {
+			results = append(results, registry.Call(ctx, call))
+		}
+		messages = append(messages, Message{Role: RoleTool, ToolResults: results})
+
+		// A forced tool would be called again on every step
+		if req.ToolChoice != ToolChoiceNone {
+			req.ToolChoice = ToolChoiceAuto
+		}
+	}
+
+	return nil, messages, fmt.Errorf("model still calling tools after %d steps", maxSteps)
+}
@@ -0,0 +1,184 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"visionflow/database"
+)
+
+// recordingClient remembers the tool choice of every request it forwards
+type recordingClient struct {
+	AIClient
+	choices []string
+}
+
+func (c *recordingClient) GenerateText(ctx context.Context, req TextGenerateRequest) (*TextGenerateResponse, error) {
+	c.choices = append(c.choices, req.ToolChoice)
+	return c.AIClient.GenerateText(ctx, req)
+}
+
+func newWeatherRegistry(t *testing.T, calls *[]string) *ToolRegistry {
+	t.Helper()
+	registry := NewToolRegistry()
+	err := registry.Register(Tool{
+		Name: "weather",
+		Parameters: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"city": map[string]interface{}{"type": "string"}},
+			"required":   []string{"city"},
+		},
+	}, func(ctx context.Context, arguments json.RawMessage) (string, error) {
+		*calls = append(*calls, string(arguments))
+		return "sunny", nil
+	})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	return registry
+}
+
+func newTestMockClient(t *testing.T) AIClient {
+	t.Helper()
+	client, err := NewMockClient(database.ModelProvider{Type: database.ProviderMock})
+	if err != nil {
+		t.Fatalf("NewMockClient: %v", err)
+	}
+	return client
+}
+
+func TestRunWithTools(t *testing.T) {
+	var calls []string
+	registry := newWeatherRegistry(t, &calls)
+	client := &recordingClient{AIClient: newTestMockClient(t)}
+
+	resp, messages, err := RunWithTools(context.Background(), client, TextGenerateRequest{
+		Model:      "mock-text",
+		Prompt:     "What is the weather?",
+		ToolChoice: "weather",
+	}, registry, 0)
+	if err != nil {
+		t.Fatalf("RunWithTools: %v", err)
+	}
+
+	// The forced tool is only forced for the first step, otherwise the model could never answer
+	if want := []string{"weather", ToolChoiceAuto}; !reflect.DeepEqual(client.choices, want) {
+		t.Errorf("tool choices = %v, want %v", client.choices, want)
+	}
+	if len(calls) != 1 {
+		t.Fatalf("handler called %d times, want 1", len(calls))
+	}
+
+	var roles []string
+	for _, msg := range messages {
+		roles = append(roles, msg.Role)
+	}
+	if want := []string{RoleUser, RoleAssistant, RoleTool, RoleAssistant}; !reflect.DeepEqual(roles, want) {
+		t.Fatalf("conversation roles = %v, want %v", roles, want)
+	}
+	call := messages[1].ToolCalls[0]
+	result := messages[2].ToolResults[0]
+	if result.CallID != call.ID || result.Content != "sunny" || result.IsError {
+		t.Errorf("tool result %+v does not answer call %+v", result, call)
+	}
+	if resp.Content == "" || resp.Content != messages[3].Content {
+		t.Errorf("final content %q does not match the last turn %q", resp.Content, messages[3].Content)
+	}
+	if resp.TotalTokens <= 0 {
+		t.Errorf("usage was not summed over the steps: %+v", resp)
+	}
+}
+
+func TestRunWithToolsKeepsToolChoiceNone(t *testing.T) {
+	var calls []string
+	registry := newWeatherRegistry(t, &calls)
+	client := &recordingClient{AIClient: newTestMockClient(t)}
+
+	_, messages, err := RunWithTools(context.Background(), client, TextGenerateRequest{
+		Model:      "mock-text",
+		Prompt:     "Just answer",
+		ToolChoice: ToolChoiceNone,
+	}, registry, 0)
+	if err != nil {
+		t.Fatalf("RunWithTools: %v", err)
+	}
+	if len(calls) != 0 || len(messages) != 2 {
+		t.Errorf("got %d tool calls and %d messages, want none and 2", len(calls), len(messages))
+	}
+	if want := []string{ToolChoiceNone}; !reflect.DeepEqual(client.choices, want) {
+		t.Errorf("tool choices = %v, want %v", client.choices, want)
+	}
+}
+
+func TestRunWithToolsStepLimit(t *testing.T) {
+	var calls []string
+	registry := newWeatherRegistry(t, &calls)
+
+	_, messages, err := RunWithTools(context.Background(), newTestMockClient(t), TextGenerateRequest{
+		Model:      "mock-text",
+		Prompt:     "What is the weather?",
+		ToolChoice: ToolChoiceRequired,
+	}, registry, 1)
+	if err == nil || !strings.Contains(err.Error(), "still calling tools after 1 steps") {
+		t.Fatalf("error = %v, want the step limit error", err)
+	}
+	// The conversation so far is returned so the caller can show or continue it
+	if len(messages) != 3 || messages[2].Role != RoleTool {
+		t.Errorf("got %d messages, want the prompt, the tool call and its result", len(messages))
+	}
+}
+
+func TestToolRegistry(t *testing.T) {
+	var calls []string
+	registry := newWeatherRegistry(t, &calls)
+	handler := func(ctx context.Context, arguments json.RawMessage) (string, error) {
+		return "", errors.New("service unavailable")
+	}
+
+	if err := registry.Register(Tool{Name: "weather"}, handler); err == nil {
+		t.Error("Register accepted a duplicate tool")
+	}
+	if err := registry.Register(Tool{}, handler); err == nil {
+		t.Error("Register accepted a tool without a name")
+	}
+	if err := registry.Register(Tool{Name: "nothing"}, nil); err == nil {
+		t.Error("Register accepted a tool without a handler")
+	}
+	if err := registry.Register(Tool{Name: "broken"}, handler); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	var names []string
+	for _, tool := range registry.Tools() {
+		names = append(names, tool.Name)
+	}
+	if want := []string{"weather", "broken"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Tools() = %v, want %v in registration order", names, want)
+	}
+
+	tests := []struct {
+		name string
+		call ToolCall
+		want string
+	}{
+		{"unknown tool", ToolCall{ID: "1", Name: "missing"}, `unknown tool "missing"`},
+		{"invalid JSON", ToolCall{ID: "2", Name: "weather", Arguments: "{"}, "arguments are not valid JSON"},
+		{"schema violation", ToolCall{ID: "3", Name: "weather", Arguments: `{"city": 3}`}, "invalid arguments: $.city: expected string"},
+		{"handler error", ToolCall{ID: "4", Name: "broken"}, "service unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := registry.Call(context.Background(), tt.call)
+			if !result.IsError || !strings.Contains(result.Content, tt.want) || result.CallID != tt.call.ID {
+				t.Errorf("Call = %+v, want an error result for %s containing %q", result, tt.call.ID, tt.want)
+			}
+		})
+	}
+	if len(calls) != 0 {
+		t.Errorf("weather handler ran for invalid calls: %v", calls)
+	}
+}
@@ -0,0 +1,51 @@
+package ai
+
+import (
+	"fmt"
+
+	"visionflow/database"
+)
+
+// Kinds of calls written to the usage ledger
+const (
+	UsageKindText          = "text"
+	UsageKindImage         = "image"
+	UsageKindVideo         = "video"
+	UsageKindAudio         = "audio"
+	UsageKindTranscription = "transcription"
+	UsageKindEmbedding     = "embedding"
+)
+
+// UsageEntry describes a successful call to be written to the usage ledger
+type UsageEntry struct {
+	ProjectID  int
+	ProviderID int
+	Kind       string // One of the UsageKind constants
+	Model      string
+	Amount     UsageAmount
+	Count      int // Outputs the call produced, e.g. images returned or texts embedded
+}
+
+// RecordUsage prices a successful call and appends it to the usage ledger.
+// Failures are only logged, the generated content has already been delivered.
+func RecordUsage(entry UsageEntry) {
+	record := database.UsageRecord{
+		ProjectID:    entry.ProjectID,
+		ProviderID:   entry.ProviderID,
+		Model:        entry.Model,
+		Kind:         entry.Kind,
+		PromptTokens: entry.Amount.PromptTokens,
+		OutputTokens: entry.Amount.OutputTokens,
+		TotalTokens:  entry.Amount.PromptTokens + entry.Amount.OutputTokens,
+		Count:        entry.Count,
+	}
+	record.Cost, record.Priced = EstimateCost(entry.Model, entry.Amount)
+
+	if config, err := database.GetModelProvider(entry.ProviderID); err == nil && config != nil {
+		record.ProviderType = string(config.Type)
+	}
+
+	if err := database.CreateUsageRecord(record); err != nil {
+		fmt.Printf("failed to record usage for %s: %v\n", entry.Model, err)
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
+	"path/filepath"
 	"strings"
 )
 
@@ -36,3 +39,13 @@ func LoadContent(pathOrURL string) ([]byte, error) {
 	}
 	return data, nil
 }
+
+// ContentName returns the file name of a URL or local file path, without any query string
+func ContentName(pathOrURL string) string {
+	if strings.HasPrefix(pathOrURL, "http://") || strings.HasPrefix(pathOrURL, "https://") {
+		if u, err := url.Parse(pathOrURL); err == nil {
+			return path.Base(u.Path)
+		}
+	}
+	return filepath.Base(pathOrURL)
+}
@@ -0,0 +1,215 @@
+package search
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"visionflow/database"
+	aiservice "visionflow/service/ai"
+)
+
+// indexBatchSize is the number of prompts embedded per request
+const indexBatchSize = 64
+
+// ErrNoEmbeddingModel is returned when no provider has an embedding model configured
+var ErrNoEmbeddingModel = errors.New("no embedding model configured, set one on a model provider in settings")
+
+// embeddingProvider returns the first provider with an embedding model
+func embeddingProvider() (*database.ModelProvider, error) {
+	configs, err := database.ListModelProviders()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list providers: %w", err)
+	}
+	for _, config := range configs {
+		if strings.TrimSpace(config.EmbeddingModel) != "" {
+			return &config, nil
+		}
+	}
+	return nil, ErrNoEmbeddingModel
+}
+
+// SearchAssets ranks assets of all projects by the cosine similarity between their prompt and the query.
+// Assets that were added or changed since the last search are embedded first.
+func SearchAssets(ctx context.Context, query string, limit int) ([]database.AssetMatch, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("search query is required")
+	}
+
+	config, err := embeddingProvider()
+	if err != nil {
+		return nil, err
+	}
+	client, err := aiservice.NewClient(*config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for %s: %w", config.Name, err)
+	}
+
+	if err := indexAssets(ctx, *config, client); err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Embed(ctx, aiservice.EmbedRequest{
+		Inputs: []string{query},
+		Model:  config.EmbeddingModel,
+		Task:   aiservice.EmbedTaskQuery,
+	})
+	if err != nil {
+		return nil, err
+	}
+	recordUsage(*config, resp)
+	if len(resp.Embeddings) != 1 {
+		return nil, fmt.Errorf("expected 1 query embedding, got %d", len(resp.Embeddings))
+	}
+	queryVector := resp.Embeddings[0]
+
+	embeddings, err := database.ListAssetEmbeddings(config.EmbeddingModel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list embeddings: %w", err)
+	}
+	assets, err := database.ListAssets(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assets: %w", err)
+	}
+	byID := make(map[int]database.Asset, len(assets))
+	for _, asset := range assets {
+		byID[asset.ID] = asset
+	}
+
+	var matches []database.AssetMatch
+	for _, embedding := range embeddings {
+		asset, ok := byID[embedding.AssetID]
+		if !ok {
+			continue
+		}
+		score := cosine(queryVector, decodeVector(embedding.Vector))
+		if score <= 0 {
+			continue
+		}
+		matches = append(matches, database.AssetMatch{Asset: asset, Score: score})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// indexAssets embeds every asset whose prompt has no vector for the configured model yet,
+// or whose prompt changed since it was embedded.
+func indexAssets(ctx context.Context, config database.ModelProvider, client aiservice.AIClient) error {
+	assets, err := database.ListAssets(0)
+	if err != nil {
+		return fmt.Errorf("failed to list assets: %w", err)
+	}
+	embeddings, err := database.ListAssetEmbeddings(config.EmbeddingModel)
+	if err != nil {
+		return fmt.Errorf("failed to list embeddings: %w", err)
+	}
+	hashes := make(map[int]string, len(embeddings))
+	for _, embedding := range embeddings {
+		hashes[embedding.AssetID] = embedding.ContentHash
+	}
+
+	var pending []database.Asset
+	for _, asset := range assets {
+		if strings.TrimSpace(asset.Prompt) == "" {
+			continue
+		}
+		if hashes[asset.ID] != contentHash(asset.Prompt) {
+			pending = append(pending, asset)
+		}
+	}
+
+	for start := 0; start < len(pending); start += indexBatchSize {
+		batch := pending[start:min(start+indexBatchSize, len(pending))]
+		inputs := make([]string, len(batch))
+		for i, asset := range batch {
+			inputs[i] = asset.Prompt
+		}
+
+		resp, err := client.Embed(ctx, aiservice.EmbedRequest{
+			Inputs: inputs,
+			Model:  config.EmbeddingModel,
+			Task:   aiservice.EmbedTaskDocument,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to index assets: %w", err)
+		}
+		recordUsage(config, resp)
+		if len(resp.Embeddings) != len(batch) {
+			return fmt.Errorf("expected %d embeddings, got %d", len(batch), len(resp.Embeddings))
+		}
+
+		for i, asset := range batch {
+			err := database.SaveAssetEmbedding(database.AssetEmbedding{
+				AssetID:     asset.ID,
+				ProviderID:  config.ID,
+				Model:       config.EmbeddingModel,
+				ContentHash: contentHash(asset.Prompt),
+				Vector:      encodeVector(resp.Embeddings[i]),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to save embedding of asset %d: %w", asset.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// recordUsage appends an embedding call to the usage ledger
+func recordUsage(config database.ModelProvider, resp *aiservice.EmbedResponse) {
+	aiservice.RecordUsage(aiservice.UsageEntry{
+		ProviderID: config.ID,
+		Kind:       aiservice.UsageKindEmbedding,
+		Model:      resp.Model,
+		Amount:     aiservice.UsageAmount{PromptTokens: resp.PromptTokens},
+		Count:      len(resp.Embeddings),
+	})
+}
+
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+func encodeVector(vector []float32) []byte {
+	data := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(data[4*i:], math.Float32bits(v))
+	}
+	return data
+}
+
+func decodeVector(data []byte) []float32 {
+	vector := make([]float32, len(data)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:]))
+	}
+	return vector
+}
+
+// cosine returns the cosine similarity of two vectors, 0 when their lengths differ or one is zero
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
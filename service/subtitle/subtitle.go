@@ -0,0 +1,120 @@
+package subtitle
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	aiservice "visionflow/service/ai"
+)
+
+const (
+	// MaxLineLength is the common broadcast limit for one caption line
+	MaxLineLength = 42
+	// MaxLines is the number of lines shown at once
+	MaxLines = 2
+	// minCueSeconds keeps very short cues readable
+	minCueSeconds = 0.5
+)
+
+// Cue is one caption shown on screen between Start and End, in seconds
+type Cue struct {
+	Start float64
+	End   float64
+	Lines []string
+}
+
+// BuildCues turns transcript segments into captions of at most MaxLines lines of MaxLineLength.
+// Longer segments are split into several cues sharing the segment's time in proportion to their
+// length. Empty segments are dropped and overlapping times are clamped.
+func BuildCues(segments []aiservice.TranscriptSegment) []Cue {
+	var cues []Cue
+	for _, segment := range segments {
+		lines := wrap(strings.Join(strings.Fields(segment.Text), " "), MaxLineLength)
+		if len(lines) == 0 {
+			continue
+		}
+
+		start, end := segment.Start, segment.End
+		if end-start < minCueSeconds {
+			end = start + minCueSeconds
+		}
+
+		total := 0
+		for _, line := range lines {
+			total += len(line)
+		}
+
+		elapsed := 0
+		for i := 0; i < len(lines); i += MaxLines {
+			group := lines[i:min(i+MaxLines, len(lines))]
+			length := 0
+			for _, line := range group {
+				length += len(line)
+			}
+			cue := Cue{
+				Start: start + (end-start)*float64(elapsed)/float64(total),
+				End:   start + (end-start)*float64(elapsed+length)/float64(total),
+				Lines: group,
+			}
+			elapsed += length
+			cues = append(cues, cue)
+		}
+	}
+
+	// Players show overlapping cues stacked, clamp each cue to end where the next one starts
+	for i := 0; i+1 < len(cues); i++ {
+		if cues[i].End > cues[i+1].Start {
+			cues[i].End = math.Max(cues[i+1].Start, cues[i].Start)
+		}
+	}
+	return cues
+}
+
+// wrap breaks text into lines of at most width characters at word boundaries.
+// Words longer than width get a line of their own.
+func wrap(text string, width int) []string {
+	var lines []string
+	var line strings.Builder
+	for _, word := range strings.Fields(text) {
+		if line.Len() > 0 && line.Len()+1+len(word) > width {
+			lines = append(lines, line.String())
+			line.Reset()
+		}
+		if line.Len() > 0 {
+			line.WriteByte(' ')
+		}
+		line.WriteString(word)
+	}
+	if line.Len() > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// SRT formats cues as a SubRip file
+func SRT(cues []Cue) string {
+	var b strings.Builder
+	for i, cue := range cues {
+		fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, timestamp(cue.Start, ","), timestamp(cue.End, ","), strings.Join(cue.Lines, "\n"))
+	}
+	return b.String()
+}
+
+// VTT formats cues as a WebVTT file
+func VTT(cues []Cue) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for _, cue := range cues {
+		// "-->" is not allowed inside cue text
+		text := strings.ReplaceAll(strings.Join(cue.Lines, "\n"), "-->", "->")
+		fmt.Fprintf(&b, "%s --> %s\n%s\n\n", timestamp(cue.Start, "."), timestamp(cue.End, "."), text)
+	}
+	return b.String()
+}
+
+// timestamp formats seconds as HH:MM:SS followed by the separator and milliseconds
+func timestamp(seconds float64, separator string) string {
+	ms := int64(math.Round(math.Max(seconds, 0) * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, separator, ms%1000)
+}
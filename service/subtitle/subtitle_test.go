@@ -0,0 +1,162 @@
+package subtitle
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	aiservice "visionflow/service/ai"
+)
+
+func TestBuildCues(t *testing.T) {
+	longWord := strings.Repeat("x", MaxLineLength+8)
+	// Four lines of 41 characters, split into two cues of two lines each
+	line := strings.Repeat("a", 20) + " " + strings.Repeat("b", 20)
+	fourLines := strings.Join([]string{line, line, line, line}, " ")
+
+	tests := []struct {
+		name     string
+		segments []aiservice.TranscriptSegment
+		want     []Cue
+	}{
+		{
+			name:     "no segments",
+			segments: nil,
+			want:     nil,
+		},
+		{
+			name: "empty segments are dropped",
+			segments: []aiservice.TranscriptSegment{
+				{Start: 0, End: 1, Text: "  "},
+				{Start: 1, End: 2, Text: "hello   world"},
+			},
+			want: []Cue{{Start: 1, End: 2, Lines: []string{"hello world"}}},
+		},
+		{
+			name:     "short cues are stretched to the minimum duration",
+			segments: []aiservice.TranscriptSegment{{Start: 3, End: 3.1, Text: "hi"}},
+			want:     []Cue{{Start: 3, End: 3 + minCueSeconds, Lines: []string{"hi"}}},
+		},
+		{
+			name: "overlapping cues end where the next starts",
+			segments: []aiservice.TranscriptSegment{
+				{Start: 0, End: 2, Text: "first"},
+				{Start: 1, End: 3, Text: "second"},
+			},
+			want: []Cue{
+				{Start: 0, End: 1, Lines: []string{"first"}},
+				{Start: 1, End: 3, Lines: []string{"second"}},
+			},
+		},
+		{
+			name: "a cue never ends before it starts",
+			segments: []aiservice.TranscriptSegment{
+				{Start: 2, End: 4, Text: "late"},
+				{Start: 1, End: 3, Text: "early"},
+			},
+			want: []Cue{
+				{Start: 2, End: 2, Lines: []string{"late"}},
+				{Start: 1, End: 3, Lines: []string{"early"}},
+			},
+		},
+		{
+			name:     "long segments are split in proportion to their length",
+			segments: []aiservice.TranscriptSegment{{Start: 10, End: 18, Text: fourLines}},
+			want: []Cue{
+				{Start: 10, End: 14, Lines: []string{line, line}},
+				{Start: 14, End: 18, Lines: []string{line, line}},
+			},
+		},
+		{
+			name:     "a word longer than a line gets a line of its own",
+			segments: []aiservice.TranscriptSegment{{Start: 0, End: 1, Text: "a " + longWord + " b"}},
+			want: []Cue{
+				{Start: 0, End: 51.0 / 52, Lines: []string{"a", longWord}},
+				{Start: 51.0 / 52, End: 1, Lines: []string{"b"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildCues(tt.segments)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d cues %+v, want %d", len(got), got, len(tt.want))
+			}
+			for i := range got {
+				if math.Abs(got[i].Start-tt.want[i].Start) > 1e-9 || math.Abs(got[i].End-tt.want[i].End) > 1e-9 {
+					t.Errorf("cue %d: got %v --> %v, want %v --> %v", i, got[i].Start, got[i].End, tt.want[i].Start, tt.want[i].End)
+				}
+				if !reflect.DeepEqual(got[i].Lines, tt.want[i].Lines) {
+					t.Errorf("cue %d: got lines %q, want %q", i, got[i].Lines, tt.want[i].Lines)
+				}
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  []string
+	}{
+		{"", 10, nil},
+		{"one two three", 7, []string{"one two", "three"}},
+		{"one two three", 13, []string{"one two three"}},
+		{"tiny enormousword x", 5, []string{"tiny", "enormousword", "x"}},
+	}
+
+	for _, tt := range tests {
+		if got := wrap(tt.text, tt.width); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("wrap(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	tests := []struct {
+		seconds   float64
+		separator string
+		want      string
+	}{
+		{0, ",", "00:00:00,000"},
+		{1.25, ".", "00:00:01.250"},
+		{3661.5, ",", "01:01:01,500"},
+		{59.9996, ",", "00:01:00,000"},
+		{-2, ".", "00:00:00.000"},
+	}
+
+	for _, tt := range tests {
+		if got := timestamp(tt.seconds, tt.separator); got != tt.want {
+			t.Errorf("timestamp(%v, %q) = %q, want %q", tt.seconds, tt.separator, got, tt.want)
+		}
+	}
+}
+
+func TestSRT(t *testing.T) {
+	cues := []Cue{
+		{Start: 0, End: 1.5, Lines: []string{"Hello", "world"}},
+		{Start: 2, End: 3, Lines: []string{"Bye"}},
+	}
+	want := "1\n00:00:00,000 --> 00:00:01,500\nHello\nworld\n\n" +
+		"2\n00:00:02,000 --> 00:00:03,000\nBye\n\n"
+	if got := SRT(cues); got != want {
+		t.Errorf("SRT() = %q, want %q", got, want)
+	}
+	if got := SRT(nil); got != "" {
+		t.Errorf("SRT(nil) = %q, want empty", got)
+	}
+}
+
+func TestVTT(t *testing.T) {
+	cues := []Cue{{Start: 61, End: 62.25, Lines: []string{"a --> b"}}}
+	want := "WEBVTT\n\n00:01:01.000 --> 00:01:02.250\na -> b\n\n"
+	if got := VTT(cues); got != want {
+		t.Errorf("VTT() = %q, want %q", got, want)
+	}
+	if got := VTT(nil); got != "WEBVTT\n\n" {
+		t.Errorf("VTT(nil) = %q, want only the header", got)
+	}
+}
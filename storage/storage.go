@@ -2,6 +2,7 @@ package storage
 
 import (
 	"archive/zip"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -48,7 +49,14 @@ func SaveAssetContent(data []byte, prefix string, ext string) (string, error) {
 	filename := fmt.Sprintf("%s_%d%s", prefix, time.Now().UnixNano(), ext)
 	fullPath := filepath.Join(assetsDir, filename)
 
-	if err := os.WriteFile(fullPath, data, 0644); err != nil {
+	// Write to a temporary file first so an interrupted write never leaves a truncated asset
+	tmpPath := fullPath + ".part"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	if err := os.Rename(tmpPath, fullPath); err != nil {
+		os.Remove(tmpPath)
 		return "", err
 	}
 
@@ -65,13 +73,23 @@ func SaveBase64Content(b64Data string, prefix string, ext string) (string, error
 }
 
 // SaveURLContent downloads content from a URL and saves it to the assets directory.
-func SaveURLContent(url string, prefix string, ext string) (string, error) {
-	resp, err := http.Get(url)
+// The download is aborted, and nothing is saved, if ctx is cancelled midway.
+func SaveURLContent(ctx context.Context, url string, prefix string, ext string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to download content: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download content: status code %d", resp.StatusCode)
+	}
+
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return "", fmt.Errorf("failed to read response body: %w", err)